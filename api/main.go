@@ -1,92 +1,270 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"movie-app-go/configs"
 	"movie-app-go/entities"
 	"movie-app-go/modules/auth"
+	"movie-app-go/modules/checkin"
+	"movie-app-go/modules/compress"
+	"movie-app-go/modules/family"
+	"movie-app-go/modules/giftcard"
+	"movie-app-go/modules/group"
+	"movie-app-go/modules/logger"
+	"movie-app-go/modules/loyalty"
 	"movie-app-go/modules/movie"
+	"movie-app-go/modules/notify"
+	"movie-app-go/modules/poster"
+	"movie-app-go/modules/pricing"
+	"movie-app-go/modules/promo"
+	"movie-app-go/modules/realtime"
+	"movie-app-go/modules/recommend"
+	"movie-app-go/modules/review"
+	"movie-app-go/modules/search"
+	"movie-app-go/modules/tickettransfer"
+	"movie-app-go/modules/transfer"
 	"movie-app-go/modules/user"
+	"movie-app-go/modules/waitlist"
+	"movie-app-go/modules/watchlist"
 	"movie-app-go/repositories"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func main() {
-	// Set the location globally
-	location, err := time.LoadLocation("Asia/Jakarta")
-	if err != nil {
-		fmt.Println("Failed to load time zone:", err)
-		return
-	}
-	time.Local = location
+	// Set Logger
+	logger.Setup(os.Stdout, slog.LevelInfo)
+
 	var (
-		movies  []entities.Movie
-		users   []entities.User
-		tickets []entities.Ticket
+		users     []entities.User
+		tickets   []entities.Ticket
+		promos    []entities.PromoCode
+		quotes    []entities.PriceQuote
+		ledger    []entities.LoyaltyEntry
+		cards     []entities.GiftCard
+		transfers []entities.Transfer
+		offers    []entities.TicketOffer
+		waiting   []entities.WaitlistEntry
+		groups    []entities.GroupBooking
+		reviews   []entities.Review
+		watching  []entities.WatchlistItem
 	)
 
 	// Load Config
-	config, err := configs.LoadConfig()
+	config, err := configs.LoadConfig(os.Args[1:])
 	if err != nil {
-		log.Fatalf("failed to load configuration: %v", err)
+		slog.Error("failed to load configuration", "error", err)
+		os.Exit(1)
 	}
 
+	// Set the location globally
+	location, err := time.LoadLocation(config.Schedule.Timezone)
+	if err != nil {
+		slog.Error("Failed to load time zone", "error", err)
+		return
+	}
+	time.Local = location
+
 	// Load Movies
 	response, err := http.Get(config.Data.Movies)
 	if err != nil {
-		log.Println("Error fetching data:", err)
+		slog.Error("Error fetching data", "error", err)
 		return
 	}
 	defer response.Body.Close()
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		log.Println("Error reading response body:", err)
+		slog.Error("Error reading response body", "error", err)
 		return
 	}
 
-	err = json.Unmarshal(body, &movies)
+	movies, skipped, err := repositories.ParseMovies(body)
 	if err != nil {
-		log.Println("Error decoding JSON:", err)
+		slog.Error("Error decoding JSON", "error", err)
 		return
 	}
+	for _, err := range skipped {
+		slog.Warn("Skipping invalid movie", "error", err)
+	}
 
 	// Set Seats
+	loadedAt := time.Now()
 	for i := range movies {
+		if movies[i].Updated_at.IsZero() {
+			movies[i].Created_at, movies[i].Updated_at = loadedAt, loadedAt
+		}
 		movies[i].Seats = repositories.GenerateSeats()
+		if movies[i].Showtime.IsZero() {
+			movies[i].Showtime, _ = repositories.NextShowtime(movies[i].Release_date, config.Schedule.DefaultShowtime, time.Now())
+		}
+	}
+
+	// Load Pricing Rules
+	demandRules, err := pricing.ParseRules(config.Pricing.DemandRules)
+	if err != nil {
+		slog.Error("Error parsing demand rules", "error", err)
+		return
 	}
 
 	// Set Router
-	router := gin.Default()
+	router := gin.New()
+	router.Use(logger.Middleware(), gin.Recovery())
 
 	// Set CORS
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = config.Cors.AllowedOrigins
 	corsConfig.AllowMethods = config.Cors.AllowedMethods
 	corsConfig.AllowHeaders = config.Cors.AllowedHeaders
+	corsConfig.ExposeHeaders = []string{logger.RequestIDHeader, "ETag"}
 	router.Use(cors.New(corsConfig))
 
+	// Set Compression
+	router.Use(compress.Middleware())
+
 	authService := auth.NewService(config.JWT.SecretKey, config.JWT.ExpiresIn)
 	middleware := auth.AuthMiddleware(authService)
+	adminOnly := auth.RequireRole(entities.RoleAdmin)
+	staffOnly := auth.RequireRole(entities.RoleStaff, entities.RoleAdmin)
 
 	ticketRepo := repositories.NewTicketRepository(tickets)
+	seatEvents := realtime.NewHub(0)
+	pricingService := pricing.NewService(map[string]float64{
+		entities.SeatPremium:    config.Pricing.PremiumMultiplier,
+		entities.SeatCouple:     config.Pricing.CoupleMultiplier,
+		entities.SeatAccessible: config.Pricing.AccessibleMultiplier,
+	}, demandRules, config.Pricing.MinDemandMultiplier, config.Pricing.MaxDemandMultiplier)
+	quoteRepo := repositories.NewQuoteRepository(quotes)
 
 	movieRepo := repositories.NewMovieRepository(movies)
-	movieUseCase := movie.NewUseCase(movieRepo)
+	reviewRepo := repositories.NewReviewRepository(reviews)
+	reviewUseCase := review.NewUseCase(reviewRepo, ticketRepo, movieRepo)
+	searchIndex := search.NewIndex()
+	movieUseCase := movie.NewUseCase(movieRepo, pricingService, reviewUseCase, searchIndex, config.Booking.MaxSeatsPerOrder)
 	movieHandler := movie.NewHandler(movieUseCase)
-	movie.SetupRouter(router, movieHandler)
+	movie.SetupRouter(router, movieHandler, middleware, adminOnly)
+
+	posterUseCase := poster.NewUseCase(movieRepo, poster.Limits{
+		CacheDir:     config.Poster.CacheDir,
+		MaxBytes:     int64(config.Poster.MaxBytes),
+		MaxPixels:    config.Poster.MaxPixels,
+		MaxWidth:     config.Poster.MaxWidth,
+		FetchTimeout: config.Poster.FetchTimeout,
+		RetryAfter:   config.Poster.RetryAfter,
+	})
+	posterHandler := poster.NewHandler(posterUseCase)
+	poster.SetupRouter(router, posterHandler)
+
+	promoRepo := repositories.NewPromoRepository(promos)
+	promoUseCase := promo.NewUseCase(promoRepo)
+	promoHandler := promo.NewHandler(promoUseCase)
+	promo.SetupRouter(router, promoHandler, middleware, adminOnly)
 
 	userRepo := repositories.NewUserRepository(users)
-	userUseCase := user.NewUseCase(userRepo, movieRepo, ticketRepo)
+
+	loyaltyRepo := repositories.NewLoyaltyRepository(ledger)
+	loyaltyUseCase := loyalty.NewUseCase(loyaltyRepo, loyalty.Program{
+		EarnRate:       config.Loyalty.EarnRate,
+		PointValue:     config.Loyalty.PointValue,
+		MaxRedeemShare: config.Loyalty.MaxRedeemShare,
+		SilverSpend:    config.Loyalty.SilverSpend,
+		GoldSpend:      config.Loyalty.GoldSpend,
+	})
+	loyaltyHandler := loyalty.NewHandler(loyaltyUseCase, userRepo)
+	loyalty.SetupRouter(router, loyaltyHandler, middleware)
+
+	userUseCase := user.NewUseCase(userRepo, movieRepo, ticketRepo, quoteRepo, seatEvents, pricingService, promoUseCase, loyaltyUseCase, config.Booking.HoldDuration, config.Booking.MaxSeatsPerOrder, config.Booking.RestrictedRating, config.Booking.PresaleWindow)
+	// Seed Staff and Admins, nobody can register these usernames first
+	for role, entries := range map[string][]string{entities.RoleAdmin: config.Auth.Admins, entities.RoleStaff: config.Auth.Staff} {
+		for _, entry := range entries {
+			account, _ := configs.ParseAccount(entry)
+			if err := userRepo.Create(entities.User{
+				ID:         uuid.NewString(),
+				Username:   account.Username,
+				Password:   account.Hash,
+				Name:       account.Username,
+				Birthdate:  account.Birthdate,
+				Role:       role,
+				Created_at: loadedAt,
+				Updated_at: loadedAt,
+			}); err != nil {
+				slog.Error("Error seeding account", "error", err, "username", account.Username, "role", role)
+				return
+			}
+		}
+	}
 	userHandler := user.NewHandler(userUseCase, authService)
-	user.SetupRouter(router, userHandler, middleware)
+	user.SetupRouter(router, userHandler, middleware, staffOnly)
+	go userUseCase.Run(context.Background(), config.Booking.HoldCheckInterval)
+
+	reviewHandler := review.NewHandler(reviewUseCase, userUseCase)
+	review.SetupRouter(router, reviewHandler, middleware, adminOnly)
+
+	recommendUseCase := recommend.NewUseCase(ticketRepo, movieRepo, userUseCase)
+	recommendHandler := recommend.NewHandler(recommendUseCase, userUseCase)
+	recommend.SetupRouter(router, recommendHandler, middleware)
+
+	familyUseCase := family.NewUseCase(userUseCase)
+	familyHandler := family.NewHandler(familyUseCase, userUseCase)
+	family.SetupRouter(router, familyHandler, middleware)
+
+	giftCardRepo := repositories.NewGiftCardRepository(cards)
+	giftCardUseCase := giftcard.NewUseCase(giftCardRepo, userUseCase)
+	giftCardHandler := giftcard.NewHandler(giftCardUseCase, userUseCase)
+	giftcard.SetupRouter(router, giftCardHandler, middleware, adminOnly)
+
+	transferRepo := repositories.NewTransferRepository(transfers)
+	transferUseCase := transfer.NewUseCase(transferRepo, userUseCase, transfer.Limits{
+		DailyLimit:       config.Transfer.DailyLimit,
+		ConfirmThreshold: config.Transfer.ConfirmThreshold,
+		ConfirmWindow:    config.Transfer.ConfirmWindow,
+	})
+	transferHandler := transfer.NewHandler(transferUseCase, userUseCase)
+	transfer.SetupRouter(router, transferHandler, middleware)
+
+	ticketOfferRepo := repositories.NewTicketOfferRepository(offers)
+	ticketTransferUseCase := tickettransfer.NewUseCase(ticketOfferRepo, userUseCase)
+	ticketTransferHandler := tickettransfer.NewHandler(ticketTransferUseCase, userUseCase)
+	tickettransfer.SetupRouter(router, ticketTransferHandler, middleware)
+
+	checkinUseCase := checkin.NewUseCase(checkin.NewSigner(config.JWT.SecretKey), userUseCase, checkin.Window{
+		OpensBefore: config.Checkin.OpensBefore,
+		LateEntry:   config.Checkin.LateEntry,
+	})
+	checkinHandler := checkin.NewHandler(checkinUseCase, userUseCase)
+	checkin.SetupRouter(router, checkinHandler, middleware, staffOnly)
+
+	notifier := notify.NewLogNotifier(slog.Default())
+	waitlistRepo := repositories.NewWaitlistRepository(waiting)
+	waitlistUseCase := waitlist.NewUseCase(waitlistRepo, userUseCase, notifier, config.Waitlist.OfferDuration)
+	waitlistHandler := waitlist.NewHandler(waitlistUseCase, userUseCase)
+	waitlist.SetupRouter(router, waitlistHandler, middleware)
+	go waitlistUseCase.Run(context.Background(), config.Waitlist.CheckInterval)
+
+	watchlistRepo := repositories.NewWatchlistRepository(watching)
+	watchlistUseCase := watchlist.NewUseCase(watchlistRepo, movieRepo, notifier)
+	watchlistHandler := watchlist.NewHandler(watchlistUseCase, userUseCase)
+	watchlist.SetupRouter(router, watchlistHandler, middleware)
+	go watchlistUseCase.Run(context.Background(), config.Watchlist.CheckInterval)
+
+	groupRepo := repositories.NewGroupBookingRepository(groups)
+	groupUseCase := group.NewUseCase(groupRepo, userUseCase, notifier, group.Limits{
+		MaxPartySize: config.Group.MaxPartySize,
+		HoldDuration: config.Group.HoldDuration,
+	})
+	groupHandler := group.NewHandler(groupUseCase, userUseCase)
+	group.SetupRouter(router, groupHandler, middleware, staffOnly)
+	go groupUseCase.Run(context.Background(), config.Group.CheckInterval)
+
+	realtimeHandler := realtime.NewHandler(seatEvents, movieRepo)
+	realtime.SetupRouter(router, realtimeHandler)
 
 	router.Run()
 }
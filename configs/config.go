@@ -1,11 +1,28 @@
 package configs
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// EnvPrefix prefixes every environment override, e.g. MOVIEAPP_JWT_SECRETKEY
+const EnvPrefix = "MOVIEAPP"
+
+const (
+	minSecretLength   = 32
+	minSecretDistinct = 10
+	maxTokenLifetime  = 30 * 24 * time.Hour
+)
+
+var weakSecrets = []string{"secret", "changeme", "password", "jwtsecret"}
+
 type Config struct {
 	Data struct {
 		Movies string
@@ -14,6 +31,67 @@ type Config struct {
 		SecretKey string
 		ExpiresIn time.Duration
 	}
+	// Auth lists the staff and admin accounts created at startup, each as
+	// "username:bcrypt-hash:YYYY-MM-DD". Registering never grants a role
+	Auth struct {
+		Admins []string
+		Staff  []string
+	}
+	Booking struct {
+		HoldDuration      time.Duration
+		HoldCheckInterval time.Duration
+		MaxSeatsPerOrder  int
+		RestrictedRating  int
+		PresaleWindow     time.Duration
+	}
+	Schedule struct {
+		Timezone        string
+		DefaultShowtime string
+	}
+	Pricing struct {
+		PremiumMultiplier    float64
+		CoupleMultiplier     float64
+		AccessibleMultiplier float64
+		DemandRules          []string
+		MinDemandMultiplier  float64
+		MaxDemandMultiplier  float64
+	}
+	Loyalty struct {
+		EarnRate       float64
+		PointValue     int
+		MaxRedeemShare float64
+		SilverSpend    int
+		GoldSpend      int
+	}
+	Transfer struct {
+		DailyLimit       int
+		ConfirmThreshold int
+		ConfirmWindow    time.Duration
+	}
+	Waitlist struct {
+		OfferDuration time.Duration
+		CheckInterval time.Duration
+	}
+	Watchlist struct {
+		CheckInterval time.Duration
+	}
+	Poster struct {
+		CacheDir     string
+		MaxBytes     int
+		MaxPixels    int
+		MaxWidth     int
+		FetchTimeout time.Duration
+		RetryAfter   time.Duration
+	}
+	Checkin struct {
+		OpensBefore time.Duration
+		LateEntry   time.Duration
+	}
+	Group struct {
+		MaxPartySize  int
+		HoldDuration  time.Duration
+		CheckInterval time.Duration
+	}
 	Cors struct {
 		AllowedOrigins []string
 		AllowedMethods []string
@@ -21,20 +99,246 @@ type Config struct {
 	}
 }
 
-// LoadConfig loads the configuration from file
-func LoadConfig() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./configs")
+// LoadConfig loads the configuration from file, then applies environment
+// variables and command-line flags on top. Precedence is flag > env > file.
+// Every field is exposed as a flag named after its dotted key
+// (--jwt.secretkey) and as an env variable (MOVIEAPP_JWT_SECRETKEY)
+func LoadConfig(args []string) (*Config, error) {
+	keys, err := configKeys(reflect.TypeOf(Config{}), "")
+	if err != nil {
+		return nil, err
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
+	flags := pflag.NewFlagSet("movie-app", pflag.ContinueOnError)
+	configFile := flags.String("config", "", "path to the configuration file (default ./configs/config.yaml)")
+	for _, key := range keys {
+		registerFlag(flags, key)
+	}
+	if err := flags.Parse(args); err != nil {
 		return nil, err
 	}
 
+	v := viper.New()
+	if *configFile != "" {
+		v.SetConfigFile(*configFile)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath("./configs")
+	}
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	for _, key := range keys {
+		if err := v.BindEnv(key.name); err != nil {
+			return nil, err
+		}
+		if err := v.BindPFlag(key.name, flags.Lookup(key.name)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		// Without an explicit path a missing file is fine, env and flags
+		// may provide everything
+		var notFound viper.ConfigFileNotFoundError
+		if *configFile != "" || !errors.As(err, &notFound) {
+			return nil, err
+		}
+	}
+
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 
 	return &config, nil
 }
+
+// Validate rejects configurations the server must not start with
+func (c *Config) Validate() error {
+	if c.Data.Movies == "" {
+		return errors.New("data.movies is required")
+	}
+	if err := validateSecret(c.JWT.SecretKey); err != nil {
+		return err
+	}
+	if c.JWT.ExpiresIn <= 0 || c.JWT.ExpiresIn > maxTokenLifetime {
+		return fmt.Errorf("jwt.expiresIn must be between 0 and %s, got %s", maxTokenLifetime, c.JWT.ExpiresIn)
+	}
+	for _, entry := range append(append([]string(nil), c.Auth.Admins...), c.Auth.Staff...) {
+		if _, err := ParseAccount(entry); err != nil {
+			return err
+		}
+	}
+	if c.Booking.HoldDuration <= 0 {
+		return fmt.Errorf("booking.holdDuration must be positive, got %s", c.Booking.HoldDuration)
+	}
+	if c.Booking.HoldCheckInterval <= 0 {
+		return fmt.Errorf("booking.holdCheckInterval must be positive, got %s", c.Booking.HoldCheckInterval)
+	}
+	if c.Booking.MaxSeatsPerOrder <= 0 {
+		return fmt.Errorf("booking.maxSeatsPerOrder must be positive, got %d", c.Booking.MaxSeatsPerOrder)
+	}
+	if c.Booking.RestrictedRating < 0 {
+		return fmt.Errorf("booking.restrictedRating must not be negative, got %d", c.Booking.RestrictedRating)
+	}
+	if c.Booking.PresaleWindow < 0 {
+		return fmt.Errorf("booking.presaleWindow must not be negative, got %s", c.Booking.PresaleWindow)
+	}
+	if _, err := time.LoadLocation(c.Schedule.Timezone); err != nil || c.Schedule.Timezone == "" {
+		return fmt.Errorf("schedule.timezone must be an IANA zone name, got %q", c.Schedule.Timezone)
+	}
+	if c.Pricing.PremiumMultiplier <= 0 || c.Pricing.CoupleMultiplier <= 0 || c.Pricing.AccessibleMultiplier <= 0 {
+		return errors.New("pricing multipliers must be positive")
+	}
+	if c.Pricing.MinDemandMultiplier <= 0 || c.Pricing.MaxDemandMultiplier < c.Pricing.MinDemandMultiplier {
+		return fmt.Errorf("pricing demand multiplier bounds [%g, %g] are invalid", c.Pricing.MinDemandMultiplier, c.Pricing.MaxDemandMultiplier)
+	}
+	if c.Loyalty.EarnRate < 0 || c.Loyalty.PointValue < 0 || c.Loyalty.MaxRedeemShare < 0 || c.Loyalty.MaxRedeemShare > 1 {
+		return errors.New("loyalty earnRate and pointValue must not be negative and maxRedeemShare must be within 0 and 1")
+	}
+	if c.Loyalty.SilverSpend <= 0 || c.Loyalty.GoldSpend <= c.Loyalty.SilverSpend {
+		return errors.New("loyalty tiers need 0 < silverSpend < goldSpend")
+	}
+	if c.Transfer.DailyLimit <= 0 || c.Transfer.ConfirmThreshold < 0 || c.Transfer.ConfirmWindow <= 0 {
+		return errors.New("transfer dailyLimit and confirmWindow must be positive and confirmThreshold must not be negative")
+	}
+	if c.Waitlist.OfferDuration <= 0 || c.Waitlist.CheckInterval <= 0 {
+		return errors.New("waitlist offerDuration and checkInterval must be positive")
+	}
+	if c.Watchlist.CheckInterval <= 0 {
+		return fmt.Errorf("watchlist.checkInterval must be positive, got %s", c.Watchlist.CheckInterval)
+	}
+	if c.Group.MaxPartySize <= c.Booking.MaxSeatsPerOrder || c.Group.HoldDuration <= 0 || c.Group.CheckInterval <= 0 {
+		return errors.New("group maxPartySize must exceed booking.maxSeatsPerOrder and holdDuration and checkInterval must be positive")
+	}
+	if c.Checkin.OpensBefore <= 0 || c.Checkin.LateEntry < 0 {
+		return errors.New("checkin opensBefore must be positive and lateEntry must not be negative")
+	}
+	if c.Poster.CacheDir == "" {
+		return errors.New("poster.cacheDir is required")
+	}
+	if c.Poster.MaxBytes <= 0 || c.Poster.MaxPixels <= 0 || c.Poster.MaxWidth <= 0 || c.Poster.FetchTimeout <= 0 || c.Poster.RetryAfter <= 0 {
+		return errors.New("poster maxBytes, maxPixels, maxWidth, fetchTimeout and retryAfter must be positive")
+	}
+	if _, err := time.Parse("15:04", c.Schedule.DefaultShowtime); err != nil {
+		return fmt.Errorf("schedule.defaultShowtime must be HH:MM, got %q", c.Schedule.DefaultShowtime)
+	}
+	return nil
+}
+
+// Account is a staff or admin account seeded at startup
+type Account struct {
+	Username  string
+	Hash      string
+	Birthdate time.Time
+}
+
+// ParseAccount splits an auth.admins or auth.staff entry into the username,
+// its bcrypt password hash and the birthdate age-rated movies are checked
+// against
+func ParseAccount(entry string) (Account, error) {
+	parts := strings.Split(entry, ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return Account{}, errors.New(`auth accounts must be "username:bcrypt-hash:YYYY-MM-DD"`)
+	}
+	account := Account{Username: parts[0], Hash: parts[1]}
+	if _, err := bcrypt.Cost([]byte(account.Hash)); err != nil {
+		return Account{}, fmt.Errorf("auth account %q needs a bcrypt password hash: %w", account.Username, err)
+	}
+	birthdate, err := time.Parse(time.DateOnly, parts[2])
+	if err != nil || birthdate.After(time.Now()) {
+		return Account{}, fmt.Errorf("auth account %q needs a past birthdate as YYYY-MM-DD, got %q", account.Username, parts[2])
+	}
+	account.Birthdate = birthdate
+	return account, nil
+}
+
+func validateSecret(secret string) error {
+	if secret == "" {
+		return fmt.Errorf("jwt.secretKey is empty, set %s_JWT_SECRETKEY or --jwt.secretkey", EnvPrefix)
+	}
+	if len(secret) < minSecretLength {
+		return fmt.Errorf("jwt.secretKey must be at least %d characters", minSecretLength)
+	}
+	distinct := make(map[rune]struct{})
+	for _, r := range secret {
+		distinct[r] = struct{}{}
+	}
+	if len(distinct) < minSecretDistinct {
+		return errors.New("jwt.secretKey is too repetitive")
+	}
+	lower := strings.ToLower(secret)
+	for _, weak := range weakSecrets {
+		if strings.Contains(lower, weak) {
+			return fmt.Errorf("jwt.secretKey must not contain %q", weak)
+		}
+	}
+	return nil
+}
+
+type configKey struct {
+	name string
+	typ  reflect.Type
+}
+
+// configKeys flattens the Config struct into viper's lower-cased dotted keys
+func configKeys(t reflect.Type, prefix string) ([]configKey, error) {
+	var keys []configKey
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := prefix + strings.ToLower(field.Name)
+		if field.Type.Kind() == reflect.Struct {
+			nested, err := configKeys(field.Type, name+".")
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, nested...)
+			continue
+		}
+		if !supportedFlagType(field.Type) {
+			return nil, fmt.Errorf("config key %s has unsupported type %s", name, field.Type)
+		}
+		keys = append(keys, configKey{name: name, typ: field.Type})
+	}
+	return keys, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func supportedFlagType(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Bool, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func registerFlag(flags *pflag.FlagSet, key configKey) {
+	usage := fmt.Sprintf("overrides %s (env %s_%s)", key.name, EnvPrefix, strings.ToUpper(strings.ReplaceAll(key.name, ".", "_")))
+	if key.typ == durationType {
+		flags.Duration(key.name, 0, usage)
+		return
+	}
+	switch key.typ.Kind() {
+	case reflect.String:
+		flags.String(key.name, "", usage)
+	case reflect.Int:
+		flags.Int(key.name, 0, usage)
+	case reflect.Bool:
+		flags.Bool(key.name, false, usage)
+	case reflect.Float64:
+		flags.Float64(key.name, 0, usage)
+	case reflect.Slice:
+		flags.StringSlice(key.name, nil, usage)
+	}
+}
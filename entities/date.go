@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout is how calendar days are written in the catalog and the API
+const DateLayout = "2006-01-02"
+
+// Date is a calendar day, held as its midnight in the local timezone and
+// written as YYYY-MM-DD. An empty string decodes to the zero Date
+type Date struct {
+	time.Time
+}
+
+// ParseDate reads a YYYY-MM-DD day in the local timezone
+func ParseDate(s string) (Date, error) {
+	day, err := time.ParseInLocation(DateLayout, s, time.Local)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD", s)
+	}
+	return Date{day}, nil
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid date %s, want a YYYY-MM-DD string", data)
+	}
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+	day, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = day
+	return nil
+}
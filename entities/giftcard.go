@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// GiftCard stores only a hash of its code, the code itself is shown once
+// when the card is issued
+type GiftCard struct {
+	ID          string    `json:"id"`
+	Code_hash   string    `json:"-"`
+	Code_suffix string    `json:"code_suffix"`
+	Amount      int       `json:"amount"`
+	Expires_at  time.Time `json:"expires_at"`
+	Issued_by   string    `json:"issued_by"`
+	Redeemed_by string    `json:"redeemed_by,omitempty"`
+	Redeemed_at time.Time `json:"redeemed_at"`
+	Created_at  time.Time `json:"created_at"`
+}
+
+func (g GiftCard) Redeemed() bool {
+	return g.Redeemed_by != ""
+}
@@ -0,0 +1,59 @@
+package entities
+
+import "time"
+
+const (
+	GroupRequested = "requested"
+	GroupApproved  = "approved"
+	GroupRejected  = "rejected"
+	GroupConfirmed = "confirmed"
+	GroupCancelled = "cancelled"
+	GroupExpired   = "expired"
+)
+
+type GroupContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// GroupShare is the part of a group booking one user pays for, the
+// organizer alone holds a single share unless the payment is split
+type GroupShare struct {
+	UserID   string    `json:"user_id"`
+	Username string    `json:"username"`
+	Seats    []string  `json:"seats"`
+	Amount   int       `json:"amount"`
+	Paid     bool      `json:"paid"`
+	Paid_at  time.Time `json:"paid_at"`
+	TicketID string    `json:"ticket_id,omitempty"`
+}
+
+// GroupBooking is a request for more seats than a single order allows.
+// Staff approve it by reserving a seat block, which stays held until every
+// share is paid or Hold_expires_at passes
+type GroupBooking struct {
+	ID                 string         `json:"id"`
+	MovieID            int            `json:"movie_id"`
+	Organizer_id       string         `json:"organizer_id"`
+	Organizer_username string         `json:"organizer_username"`
+	PartySize          int            `json:"party_size"`
+	Contact            GroupContact   `json:"contact"`
+	Note               string         `json:"note,omitempty"`
+	Status             string         `json:"status"`
+	Seats              []string       `json:"seats,omitempty"`
+	Breakdown          PriceBreakdown `json:"breakdown"`
+	Shares             []GroupShare   `json:"shares,omitempty"`
+	Reviewed_by        string         `json:"reviewed_by,omitempty"`
+	Review_note        string         `json:"review_note,omitempty"`
+	Reviewed_at        time.Time      `json:"reviewed_at"`
+	Hold_expires_at    time.Time      `json:"hold_expires_at"`
+	Created_at         time.Time      `json:"created_at"`
+	Updated_at         time.Time      `json:"updated_at"`
+}
+
+// Holder is the name the reserved seats are held under, no username can
+// take them through a regular order
+func (g GroupBooking) Holder() string {
+	return "group:" + g.ID
+}
@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// Loyalty ledger entry kinds
+const (
+	LoyaltyEarn          = "earn"
+	LoyaltyRedeem        = "redeem"
+	LoyaltyReverseEarn   = "reverse_earn"
+	LoyaltyReverseRedeem = "reverse_redeem"
+)
+
+// Loyalty tiers
+const (
+	TierBronze = "bronze"
+	TierSilver = "silver"
+	TierGold   = "gold"
+)
+
+// LoyaltyEntry is one line of a user's points ledger. Points are signed and
+// Spend is the cash spend an entry counts towards the yearly tier
+type LoyaltyEntry struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	TicketID   string    `json:"ticket_id"`
+	Kind       string    `json:"kind"`
+	Points     int       `json:"points"`
+	Spend      int       `json:"spend"`
+	Created_at time.Time `json:"created_at"`
+}
@@ -1,16 +1,52 @@
 package entities
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Movie struct {
-	ID           int       `json:"id"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	Release_date string    `json:"release_date"`
-	Age_rating   int       `json:"age_rating"`
-	Poster_url   string    `json:"poster_url"`
-	Ticket_price int       `json:"ticket_price"`
-	Seats        []Seat    `json:"seats"`
-	Created_at   time.Time `json:"created_at"`
-	Updated_at   time.Time `json:"updated_at"`
+	ID           int                    `json:"id"`
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	Release_date Date                   `json:"release_date"`
+	Age_rating   int                    `json:"age_rating"`
+	Poster_url   string                 `json:"poster_url"`
+	Ticket_price int                    `json:"ticket_price"`
+	Genres       []string               `json:"genres,omitempty"`
+	Runtime      int                    `json:"runtime,omitempty"`
+	Language     string                 `json:"language,omitempty"`
+	Director     string                 `json:"director,omitempty"`
+	Cast         []string               `json:"cast,omitempty"`
+	Trailer_url  string                 `json:"trailer_url,omitempty"`
+	Showtime     time.Time              `json:"showtime"`
+	Seat_pricing map[string]SeatPricing `json:"seat_pricing,omitempty"`
+	Seats        []Seat                 `json:"seats"`
+	Rating       *RatingSummary         `json:"rating,omitempty"`
+	Created_at   time.Time              `json:"created_at"`
+	Updated_at   time.Time              `json:"updated_at"`
+}
+
+// HasGenre matches a genre case-insensitively
+func (m Movie) HasGenre(genre string) bool {
+	for _, g := range m.Genres {
+		if strings.EqualFold(g, genre) {
+			return true
+		}
+	}
+	return false
+}
+
+// Released tells whether the release day has started, movies without a
+// release date count as released
+func (m Movie) Released(now time.Time) bool {
+	return m.Release_date.IsZero() || !now.Before(m.Release_date.Time)
+}
+
+// SeatPricing overrides the default price of a seat type for one movie, with
+// either a fixed price or a multiplier of Ticket_price. A fixed price wins when
+// both are given
+type SeatPricing struct {
+	Multiplier float64 `json:"multiplier,omitempty"`
+	Price      int     `json:"price,omitempty"`
 }
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// Promo code discount types
+const (
+	PromoPercentage = "percentage"
+	PromoFixed      = "fixed"
+)
+
+type PromoCode struct {
+	Code           string    `json:"code"`
+	Type           string    `json:"type"`
+	Value          int       `json:"value"`
+	Max_discount   int       `json:"max_discount"`
+	Min_spend      int       `json:"min_spend"`
+	Per_user_limit int       `json:"per_user_limit"`
+	Global_limit   int       `json:"global_limit"`
+	Used           int       `json:"used"`
+	Valid_from     time.Time `json:"valid_from"`
+	Valid_until    time.Time `json:"valid_until"`
+	Movie_ids      []int     `json:"movie_ids"`
+	Active         bool      `json:"active"`
+	Created_at     time.Time `json:"created_at"`
+	Updated_at     time.Time `json:"updated_at"`
+}
+
+type PromoRedemption struct {
+	ID         string    `json:"id"`
+	Code       string    `json:"code"`
+	UserID     string    `json:"user_id"`
+	TicketID   string    `json:"ticket_id"`
+	Discount   int       `json:"discount"`
+	Created_at time.Time `json:"created_at"`
+}
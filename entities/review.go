@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// Review statuses, hidden reviews are left out of ratings until restored
+const (
+	ReviewPublished = "published"
+	ReviewHidden    = "hidden"
+)
+
+type Review struct {
+	ID              string    `json:"id"`
+	MovieID         int       `json:"movie_id"`
+	UserID          string    `json:"user_id"`
+	Username        string    `json:"username"`
+	Rating          int       `json:"rating"`
+	Text            string    `json:"text"`
+	Status          string    `json:"status"`
+	Moderated_by    string    `json:"moderated_by,omitempty"`
+	Moderation_note string    `json:"moderation_note,omitempty"`
+	Moderated_at    time.Time `json:"moderated_at"`
+	Created_at      time.Time `json:"created_at"`
+	Updated_at      time.Time `json:"updated_at"`
+}
+
+// RatingSummary aggregates the published reviews of a movie
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
@@ -1,7 +1,43 @@
 package entities
 
+import "time"
+
+// Seat statuses as shown to customers
+const (
+	SeatAvailable = "available"
+	SeatBooked    = "booked"
+	SeatHeld      = "held"
+	SeatBlocked   = "blocked"
+)
+
+// Seat types
+const (
+	SeatRegular    = "regular"
+	SeatPremium    = "premium"
+	SeatCouple     = "couple"
+	SeatAccessible = "accessible"
+)
+
 type Seat struct {
-	Row    string
-	Number int
-	Booked bool
+	Row       string
+	Number    int
+	Booked    bool
+	Blocked   bool
+	Type      string
+	HeldBy    string
+	HeldUntil time.Time
+}
+
+// Status reports the seat state at the given time, an expired hold counts as
+// available again
+func (s Seat) Status(at time.Time) string {
+	switch {
+	case s.Blocked:
+		return SeatBlocked
+	case s.Booked:
+		return SeatBooked
+	case s.HeldBy != "" && at.Before(s.HeldUntil):
+		return SeatHeld
+	}
+	return SeatAvailable
 }
@@ -3,11 +3,94 @@ package entities
 import "time"
 
 type Ticket struct {
-	ID         string
-	UserID     string
-	Movie      Movie
-	Seats      []Seat
-	Cost       int
-	Created_At time.Time
-	Updated_At time.Time
+	ID             string
+	UserID         string
+	Movie          Movie
+	Seats          []Seat
+	Cost           int
+	Breakdown      PriceBreakdown
+	Attendees      []Attendee
+	PreviousOwners []TicketOwner
+	CheckedInAt    time.Time
+	CheckedInBy    string
+	Created_At     time.Time
+	Updated_At     time.Time
+}
+
+// CheckedIn tells whether the ticket was already used at the door
+func (t Ticket) CheckedIn() bool {
+	return !t.CheckedInAt.IsZero()
+}
+
+// Attendee is who sits in a seat of a ticket, the purchaser or one of
+// their dependents
+type Attendee struct {
+	Seat      string `json:"seat"`
+	ProfileID string `json:"profile_id"`
+	Name      string `json:"name"`
+}
+
+// TicketOwner records a user who handed the ticket on to someone else,
+// Ticket.PreviousOwners lists them oldest first
+type TicketOwner struct {
+	UserID         string    `json:"user_id"`
+	Username       string    `json:"username"`
+	Transferred_at time.Time `json:"transferred_at"`
+}
+
+type PriceLine struct {
+	Seat       string  `json:"seat"`
+	Type       string  `json:"type"`
+	BasePrice  int     `json:"base_price"`
+	Multiplier float64 `json:"multiplier"`
+	Price      int     `json:"price"`
+}
+
+// Price adjustment kinds
+const (
+	AdjustmentPromo  = "promo"
+	AdjustmentPoints = "points"
+)
+
+// PriceAdjustment is a change applied to the subtotal, discounts are
+// negative amounts
+type PriceAdjustment struct {
+	Kind        string `json:"kind"`
+	Code        string `json:"code,omitempty"`
+	Description string `json:"description"`
+	Amount      int    `json:"amount"`
+	Points      int    `json:"points,omitempty"`
+}
+
+type PriceBreakdown struct {
+	Items            []PriceLine       `json:"items"`
+	DemandMultiplier float64           `json:"demand_multiplier"`
+	Subtotal         int               `json:"subtotal"`
+	Adjustments      []PriceAdjustment `json:"adjustments,omitempty"`
+	Total            int               `json:"total"`
+	QuoteID          string            `json:"quote_id,omitempty"`
+	QuotedAt         time.Time         `json:"quoted_at"`
+}
+
+// PriceQuote locks the price of seats picked by a user until it expires
+type PriceQuote struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	MovieID    int            `json:"movie_id"`
+	Seats      []string       `json:"seats"`
+	Breakdown  PriceBreakdown `json:"breakdown"`
+	Expires_at time.Time      `json:"expires_at"`
+}
+
+// Adjust records an adjustment and recomputes the total, which never drops
+// below zero
+func (b *PriceBreakdown) Adjust(adjustment PriceAdjustment) {
+	b.Adjustments = append(b.Adjustments, adjustment)
+	b.Total = b.Subtotal
+	for _, a := range b.Adjustments {
+		b.Total += a.Amount
+	}
+	if b.Total < 0 {
+		b.Total = 0
+	}
 }
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+const (
+	OfferPending   = "pending"
+	OfferAccepted  = "accepted"
+	OfferDeclined  = "declined"
+	OfferCancelled = "cancelled"
+	OfferExpired   = "expired"
+)
+
+// TicketOffer is a ticket handed to another user, waiting for them to
+// accept or decline it. The ticket stays with the sender until accepted
+type TicketOffer struct {
+	ID            string    `json:"id"`
+	TicketID      string    `json:"ticket_id"`
+	MovieID       int       `json:"movie_id"`
+	MovieTitle    string    `json:"movie_title"`
+	Seats         []string  `json:"seats"`
+	From_user_id  string    `json:"from_user_id"`
+	From_username string    `json:"from_username"`
+	To_user_id    string    `json:"to_user_id"`
+	To_username   string    `json:"to_username"`
+	Status        string    `json:"status"`
+	Expires_at    time.Time `json:"expires_at"`
+	Responded_at  time.Time `json:"responded_at"`
+	Created_at    time.Time `json:"created_at"`
+}
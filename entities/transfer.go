@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+const (
+	TransferPending   = "pending"
+	TransferCompleted = "completed"
+	TransferCancelled = "cancelled"
+	TransferExpired   = "expired"
+)
+
+// Transfer moves balance from one user to another. A single record serves
+// both sides, the sender and the recipient each see it in their history
+type Transfer struct {
+	ID            string    `json:"id"`
+	From_user_id  string    `json:"from_user_id"`
+	From_username string    `json:"from_username"`
+	To_user_id    string    `json:"to_user_id"`
+	To_username   string    `json:"to_username"`
+	Amount        int       `json:"amount"`
+	Note          string    `json:"note,omitempty"`
+	Status        string    `json:"status"`
+	Expires_at    time.Time `json:"expires_at"`
+	Completed_at  time.Time `json:"completed_at"`
+	Created_at    time.Time `json:"created_at"`
+}
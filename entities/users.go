@@ -2,14 +2,56 @@ package entities
 
 import "time"
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleStaff = "staff"
+	RoleAdmin = "admin"
+)
+
 type User struct {
-	ID         string    `json:"id"`
-	Username   string    `json:"username"`
-	Password   string    `json:"-"`
-	Name       string    `json:"name"`
-	Age        int       `json:"age"`
-	Balance    int       `json:"balance"`
-	Ticket     []Ticket  `json:"ticket"`
-	Created_at time.Time `json:"-"`
-	Updated_at time.Time `json:"-"`
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Password string `json:"-"`
+	Name     string `json:"name"`
+	// Age is the age typed in by accounts registered before dates of birth
+	// were collected, it only counts while Birthdate is unset
+	Age            int       `json:"age,omitempty"`
+	Birthdate      time.Time `json:"birthdate"`
+	ID_verified    bool      `json:"id_verified"`
+	ID_verified_by string    `json:"id_verified_by,omitempty"`
+	ID_verified_at time.Time `json:"id_verified_at"`
+	Role           string    `json:"role"`
+	Balance        int       `json:"balance"`
+	// Guardian_id links a dependent profile to the account managing it
+	Guardian_id string `json:"guardian_id,omitempty"`
+	// Spending_limit caps what a dependent pays out of their wallet per
+	// calendar month, zero means no limit. Spent is the total so far in
+	// Spent_month, refunds do not give the allowance back
+	Spending_limit int       `json:"spending_limit,omitempty"`
+	Spent          int       `json:"spent,omitempty"`
+	Spent_month    string    `json:"spent_month,omitempty"`
+	Ticket         []Ticket  `json:"ticket"`
+	Created_at     time.Time `json:"-"`
+	Updated_at     time.Time `json:"-"`
+}
+
+// Dependent tells whether the profile is managed by a guardian
+func (u User) Dependent() bool {
+	return u.Guardian_id != ""
+}
+
+// AgeAt returns the user's age on the calendar day of t in t's location.
+// Accounts without a Birthdate report the age they registered with
+func (u User) AgeAt(t time.Time) int {
+	if u.Birthdate.IsZero() {
+		return u.Age
+	}
+	year, month, day := t.Date()
+	bYear, bMonth, bDay := u.Birthdate.Date()
+	age := year - bYear
+	if month < bMonth || (month == bMonth && day < bDay) {
+		age--
+	}
+	return age
 }
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+const (
+	WaitlistWaiting = "waiting"
+	WaitlistOffered = "offered"
+	WaitlistBooked  = "booked"
+	WaitlistExpired = "expired"
+	WaitlistLeft    = "left"
+)
+
+// WaitlistEntry queues a party for a sold-out movie. When seats free up the
+// entry is offered a hold on them until Offer_expires_at
+type WaitlistEntry struct {
+	ID               string    `json:"id"`
+	MovieID          int       `json:"movie_id"`
+	UserID           string    `json:"user_id"`
+	Username         string    `json:"username"`
+	PartySize        int       `json:"party_size"`
+	Status           string    `json:"status"`
+	Seats            []string  `json:"seats,omitempty"`
+	Offer_expires_at time.Time `json:"offer_expires_at"`
+	Created_at       time.Time `json:"created_at"`
+	Updated_at       time.Time `json:"updated_at"`
+}
+
+// Active tells whether the entry still holds a place in the queue
+func (w WaitlistEntry) Active() bool {
+	return w.Status == WaitlistWaiting || w.Status == WaitlistOffered
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// WatchlistItem is a movie a user saved. Seen_showtime and
+// Release_notified remember what the user was last told about it, so
+// reminders go out once
+type WatchlistItem struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"user_id"`
+	Username         string    `json:"username"`
+	MovieID          int       `json:"movie_id"`
+	Seen_showtime    time.Time `json:"-"`
+	Release_notified bool      `json:"-"`
+	Added_at         time.Time `json:"added_at"`
+}
@@ -8,6 +8,7 @@ import (
 
 type Claims struct {
 	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwt.StandardClaims
 }
 
@@ -17,7 +18,7 @@ type AuthService struct {
 }
 
 type AuthInterface interface {
-	GenerateToken(username string) (string, error)
+	GenerateToken(username, role string) (string, error)
 	VerifyToken(tokenString string) (*Claims, error)
 }
 
@@ -28,9 +29,10 @@ func NewService(secretKey string, expiresIn time.Duration) AuthInterface {
 	}
 }
 
-func (a *AuthService) GenerateToken(username string) (string, error) {
+func (a *AuthService) GenerateToken(username, role string) (string, error) {
 	claims := &Claims{
 		Username: username,
+		Role:     role,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(a.expiresIn).Unix(),
 		},
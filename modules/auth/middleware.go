@@ -4,18 +4,22 @@ import (
 	"net/http"
 	"strings"
 
+	"movie-app-go/modules/logger"
+
 	"github.com/gin-gonic/gin"
 )
 
 type AuthInfo struct {
 	Username string `json:"username"`
+	Role     string `json:"role"`
 }
 
 func AuthMiddleware(authService AuthInterface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header"})
+			logger.FromContext(c).Warn("AuthMiddleware.01", "error", "missing authorization header")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header", "request_id": logger.RequestID(c)})
 			c.Abort()
 			return
 		}
@@ -24,15 +28,36 @@ func AuthMiddleware(authService AuthInterface) gin.HandlerFunc {
 		claims, err := authService.VerifyToken(token)
 
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			logger.FromContext(c).Warn("AuthMiddleware.02", "error", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "request_id": logger.RequestID(c)})
 			c.Abort()
 			return
 		}
 		authInfo := AuthInfo{
 			Username: claims.Username,
+			Role:     claims.Role,
 		}
 		c.Set("AuthInfo", authInfo)
+		logger.SetUsername(c, claims.Username)
 
 		c.Next()
 	}
 }
+
+// RequireRole only lets through requests whose token carries one of the
+// roles, it must run after AuthMiddleware
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authInfo, _ := c.Get("AuthInfo")
+		info, _ := authInfo.(AuthInfo)
+		for _, role := range roles {
+			if info.Role == role {
+				c.Next()
+				return
+			}
+		}
+		logger.FromContext(c).Warn("RequireRole.01", "error", "forbidden", "role", info.Role)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden", "request_id": logger.RequestID(c)})
+		c.Abort()
+	}
+}
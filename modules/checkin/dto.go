@@ -0,0 +1,16 @@
+package checkin
+
+type (
+	QRRequest struct {
+		Size int `form:"size" binding:"omitempty,min=64,max=1024"`
+	}
+	CheckInRequest struct {
+		Token string `json:"token" binding:"required,max=256"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
@@ -0,0 +1,128 @@
+package checkin
+
+import (
+	"errors"
+	"net/http"
+
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	checkinUseCase UseCaseInterface
+	tickets        Tickets
+}
+
+type HandlerInterface interface {
+	GetTicketQR(c *gin.Context)
+	CheckIn(c *gin.Context)
+}
+
+func NewHandler(checkinUseCase UseCaseInterface, tickets Tickets) HandlerInterface {
+	return &handler{
+		checkinUseCase: checkinUseCase,
+		tickets:        tickets,
+	}
+}
+
+func (h handler) GetTicketQR(c *gin.Context) {
+	var req QRRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetTicketQR.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.tickets.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetTicketQR.02", "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	png, err := h.checkinUseCase.QRCode(user, c.Param("id"), req.Size)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.GetTicketQR.03", "error", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrNotOwned) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_QR",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	// The code admits whoever holds it, keep it out of shared caches
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+func (h handler) CheckIn(c *gin.Context) {
+	var req CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.CheckIn.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	admission, err := h.checkinUseCase.CheckIn(req.Token, authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.CheckIn.02", "error", err)
+
+		status := http.StatusInternalServerError
+		var data any = err.Error()
+		switch {
+		case errors.Is(err, ErrInvalidToken):
+			status = http.StatusUnauthorized
+		case errors.Is(err, ErrTicketCancelled), errors.Is(err, ErrTicketTransferred):
+			status = http.StatusGone
+		case errors.Is(err, ErrAlreadyCheckedIn):
+			// Show when and by whom, ushers settle disputes with it
+			status = http.StatusConflict
+			data = admission
+		case errors.Is(err, ErrNotOpen):
+			status = http.StatusConflict
+			data = admission
+		case errors.Is(err, ErrScreeningOver):
+			status = http.StatusGone
+			data = admission
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   err.Error(),
+			Data:      data,
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "ADMITTED",
+		Data:    admission,
+	})
+}
@@ -0,0 +1,12 @@
+package checkin
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc, staffOnly gin.HandlerFunc) {
+	r.POST("/checkin", middleware, staffOnly, h.CheckIn)
+
+	UserRouter := r.Group("/user")
+	UserRouter.GET("/tickets/:id/qr", middleware, h.GetTicketQR)
+}
@@ -0,0 +1,142 @@
+package checkin
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRSize = 256
+	maxQRSize     = 1024
+)
+
+var (
+	ErrNotOwned          = errors.New("TICKET_NOT_OWNED")
+	ErrTicketCancelled   = errors.New("TICKET_CANCELLED")
+	ErrTicketTransferred = errors.New("TICKET_TRANSFERRED")
+	ErrAlreadyCheckedIn  = errors.New("ALREADY_CHECKED_IN")
+	ErrNotOpen           = errors.New("CHECK_IN_NOT_OPEN")
+	ErrScreeningOver     = errors.New("SCREENING_OVER")
+)
+
+// Window is when a ticket admits its holder, relative to the showtime of
+// the screening it was bought for
+type Window struct {
+	OpensBefore time.Duration
+	LateEntry   time.Duration
+}
+
+// Tickets is the part of the user use case check-in works through
+type Tickets interface {
+	GetUser(username string) (entities.User, error)
+	GetTicket(id string) (entities.Ticket, error)
+	CheckIn(ticketID, staff string) (entities.Ticket, error)
+}
+
+// Admission is what the usher sees after scanning a ticket
+type Admission struct {
+	TicketID      string    `json:"ticket_id"`
+	MovieID       int       `json:"movie_id"`
+	MovieTitle    string    `json:"movie_title"`
+	Showtime      time.Time `json:"showtime"`
+	Seats         []string  `json:"seats"`
+	Checked_in_at time.Time `json:"checked_in_at"`
+	Checked_in_by string    `json:"checked_in_by"`
+}
+
+type useCase struct {
+	// mu makes checking and marking a ticket used one step
+	mu      sync.Mutex
+	signer  *Signer
+	tickets Tickets
+	window  Window
+}
+
+type UseCaseInterface interface {
+	QRCode(u entities.User, ticketID string, size int) ([]byte, error)
+	CheckIn(token, staff string) (Admission, error)
+}
+
+func NewUseCase(signer *Signer, tickets Tickets, window Window) UseCaseInterface {
+	return &useCase{
+		signer:  signer,
+		tickets: tickets,
+		window:  window,
+	}
+}
+
+// QRCode renders the signed token of one of the user's tickets as a PNG
+func (usecase *useCase) QRCode(u entities.User, ticketID string, size int) ([]byte, error) {
+	ticket, err := usecase.tickets.GetTicket(ticketID)
+	if err != nil || ticket.UserID != u.ID {
+		return nil, ErrNotOwned
+	}
+	if size <= 0 {
+		size = defaultQRSize
+	}
+	if size > maxQRSize {
+		size = maxQRSize
+	}
+	return qrcode.Encode(usecase.signer.Sign(ticket.ID, ticket.UserID), qrcode.Medium, size)
+}
+
+// CheckIn admits the seats of a scanned ticket once, within the window
+// around its screening. A ticket whose signature is valid but which no
+// longer exists was cancelled
+func (usecase *useCase) CheckIn(token, staff string) (Admission, error) {
+	ticketID, userID, err := usecase.signer.Verify(token)
+	if err != nil {
+		return Admission{}, err
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	ticket, err := usecase.tickets.GetTicket(ticketID)
+	if err != nil {
+		return Admission{}, ErrTicketCancelled
+	}
+	if ticket.UserID != userID {
+		return Admission{}, ErrTicketTransferred
+	}
+	if ticket.CheckedIn() {
+		return admission(ticket), ErrAlreadyCheckedIn
+	}
+	// Movies move on to the next day's screening once a show starts, so the
+	// ticket's own showtime says which screening it is for
+	now := time.Now()
+	showtime := ticket.Movie.Showtime
+	if now.Before(showtime.Add(-usecase.window.OpensBefore)) {
+		return admission(ticket), ErrNotOpen
+	}
+	if now.After(showtime.Add(usecase.window.LateEntry)) {
+		return admission(ticket), ErrScreeningOver
+	}
+	ticket, err = usecase.tickets.CheckIn(ticket.ID, staff)
+	if err != nil {
+		return Admission{}, err
+	}
+	return admission(ticket), nil
+}
+
+func admission(t entities.Ticket) Admission {
+	seats := make([]string, 0, len(t.Seats))
+	for _, seat := range t.Seats {
+		seats = append(seats, fmt.Sprintf("%s%d", seat.Row, seat.Number))
+	}
+	return Admission{
+		TicketID:      t.ID,
+		MovieID:       t.Movie.ID,
+		MovieTitle:    t.Movie.Title,
+		Showtime:      t.Movie.Showtime,
+		Seats:         seats,
+		Checked_in_at: t.CheckedInAt,
+		Checked_in_by: t.CheckedInBy,
+	}
+}
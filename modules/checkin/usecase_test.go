@@ -0,0 +1,66 @@
+package checkin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+)
+
+// fakeTickets serves one ticket owned by alice
+type fakeTickets struct {
+	ticket entities.Ticket
+}
+
+func (f *fakeTickets) GetUser(username string) (entities.User, error) {
+	return entities.User{ID: "u-" + username, Username: username}, nil
+}
+
+func (f *fakeTickets) GetTicket(id string) (entities.Ticket, error) {
+	if id != f.ticket.ID {
+		return entities.Ticket{}, errors.New("NOT_FOUND")
+	}
+	return f.ticket, nil
+}
+
+func (f *fakeTickets) CheckIn(ticketID, staff string) (entities.Ticket, error) {
+	f.ticket.CheckedInAt = time.Now()
+	f.ticket.CheckedInBy = staff
+	return f.ticket, nil
+}
+
+func TestCheckInWindow(t *testing.T) {
+	window := Window{OpensBefore: time.Hour, LateEntry: 30 * time.Minute}
+	tests := []struct {
+		name string
+		// showAt is the ticket's showtime relative to now
+		showAt time.Duration
+		want   error
+	}{
+		{name: "doors open", showAt: 45 * time.Minute},
+		{name: "late entry", showAt: -20 * time.Minute},
+		{name: "too early", showAt: 2 * time.Hour, want: ErrNotOpen},
+		{name: "screening over", showAt: -time.Hour, want: ErrScreeningOver},
+		{name: "yesterday's screening", showAt: -24 * time.Hour, want: ErrScreeningOver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := NewSigner("0123456789abcdefghijklmnopqrstuv")
+			tickets := &fakeTickets{ticket: entities.Ticket{
+				ID:     "t-1",
+				UserID: "u-alice",
+				Movie:  entities.Movie{ID: 1, Showtime: time.Now().Add(tt.showAt)},
+			}}
+			usecase := NewUseCase(signer, tickets, window)
+
+			_, err := usecase.CheckIn(signer.Sign("t-1", "u-alice"), "usher")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			if tickets.ticket.CheckedIn() != (tt.want == nil) {
+				t.Errorf("checked in = %v", tickets.ticket.CheckedIn())
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Supported content codings, preferred in this order when the client
+// weighs them equally
+const (
+	Brotli = "br"
+	Gzip   = "gzip"
+)
+
+var preferred = []string{Brotli, Gzip}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var pools = map[string]*sync.Pool{
+	Brotli: {New: func() any { return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression) }},
+	Gzip:   {New: func() any { return gzip.NewWriter(io.Discard) }},
+}
+
+// Middleware compresses text and JSON responses with the best coding the
+// client accepts. Event streams, images and responses that already carry a
+// Content-Encoding pass through untouched. A strong ETag on a compressed
+// response gets the coding appended, the bytes differ from the identity
+// representation
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		coding := Negotiate(c.GetHeader("Accept-Encoding"))
+		if coding == "" {
+			c.Next()
+			return
+		}
+
+		w := &writer{ResponseWriter: c.Writer, coding: coding}
+		c.Writer = w
+		// gin writes its own 404 and 405 bodies after the chain returns,
+		// those go out uncompressed
+		defer func() {
+			w.close()
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// Negotiate picks the coding to use from an Accept-Encoding header, or ""
+// for none
+func Negotiate(header string) string {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				weight = q
+			}
+		}
+		weights[name] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, coding := range preferred {
+		weight, ok := weights[coding]
+		if !ok {
+			weight, ok = weights["*"]
+		}
+		if ok && weight > bestWeight {
+			best, bestWeight = coding, weight
+		}
+	}
+	return best
+}
+
+// SuffixETag marks a strong ETag as belonging to the coded representation
+func SuffixETag(etag, coding string) string {
+	if !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) || len(etag) < 2 {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + coding + `"`
+}
+
+// StripETag undoes SuffixETag so a client's copy of a coded representation
+// matches the identity ETag
+func StripETag(etag string) string {
+	for _, coding := range preferred {
+		suffix := "-" + coding + `"`
+		if strings.HasSuffix(etag, suffix) {
+			return etag[:len(etag)-len(suffix)] + `"`
+		}
+	}
+	return etag
+}
+
+// writer decides on the first write whether the response is compressed,
+// once the handler has set its headers
+type writer struct {
+	gin.ResponseWriter
+	coding  string
+	decided bool
+	encoder encoder
+}
+
+func (w *writer) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	header := w.Header()
+	status := w.Status()
+	if status == http.StatusNotModified {
+		// Keep the validator the client stored with the coded body, and
+		// tell caches it belongs to that coding
+		if etag := header.Get("ETag"); etag != "" {
+			header.Set("ETag", SuffixETag(etag, w.coding))
+			header.Add("Vary", "Accept-Encoding")
+		}
+		return
+	}
+	if !compressible(header.Get("Content-Type")) || header.Get("Content-Encoding") != "" ||
+		status < http.StatusOK || status == http.StatusNoContent {
+		return
+	}
+
+	header.Set("Content-Encoding", w.coding)
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	if etag := header.Get("ETag"); etag != "" {
+		header.Set("ETag", SuffixETag(etag, w.coding))
+	}
+	w.encoder = pools[w.coding].Get().(encoder)
+	w.encoder.Reset(w.ResponseWriter)
+}
+
+func (w *writer) Write(data []byte) (int, error) {
+	w.decide()
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.encoder.Write(data)
+}
+
+func (w *writer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *writer) WriteHeaderNow() {
+	w.decide()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *writer) Flush() {
+	w.decide()
+	if w.encoder != nil {
+		w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *writer) close() {
+	if w.encoder == nil {
+		return
+	}
+	w.encoder.Close()
+	w.encoder.Reset(io.Discard)
+	pools[w.coding].Put(w.encoder)
+	w.encoder = nil
+}
+
+// compressible leaves out event streams, which must reach the client as
+// they are written, and already compressed media
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,22 @@
+package family
+
+type (
+	CreateRequest struct {
+		Name           string `json:"name" binding:"required,max=100"`
+		Birthdate      string `json:"birthdate" binding:"required,datetime=2006-01-02"`
+		Username       string `json:"username" binding:"required_with=Password"`
+		Password       string `json:"password" binding:"required_with=Username"`
+		Spending_limit int    `json:"spending_limit" binding:"min=0"`
+	}
+	UpdateRequest struct {
+		Name           *string `json:"name" binding:"omitempty,min=1,max=100"`
+		Birthdate      *string `json:"birthdate" binding:"omitempty,datetime=2006-01-02"`
+		Spending_limit *int    `json:"spending_limit" binding:"omitempty,min=0"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
@@ -0,0 +1,177 @@
+package family
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dateLayout is how dates of birth are sent and received
+const dateLayout = "2006-01-02"
+
+type handler struct {
+	familyUseCase UseCaseInterface
+	accounts      Accounts
+}
+
+type HandlerInterface interface {
+	AddDependent(c *gin.Context)
+	GetDependents(c *gin.Context)
+	UpdateDependent(c *gin.Context)
+}
+
+func NewHandler(familyUseCase UseCaseInterface, accounts Accounts) HandlerInterface {
+	return &handler{
+		familyUseCase: familyUseCase,
+		accounts:      accounts,
+	}
+}
+
+func (h handler) AddDependent(c *gin.Context) {
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.AddDependent.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	guardian, ok := h.currentUser(c, "Handler.AddDependent.02")
+	if !ok {
+		return
+	}
+	profile := Profile{
+		Name:          req.Name,
+		Username:      req.Username,
+		SpendingLimit: req.Spending_limit,
+	}
+	profile.Birthdate, _ = time.Parse(dateLayout, req.Birthdate)
+	if req.Password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			logger.FromContext(c).Error("Handler.AddDependent.03", "error", err)
+
+			c.JSON(http.StatusBadRequest, Response{
+				Code:      http.StatusBadRequest,
+				Message:   "FAILED_HASH_PASSWORD",
+				Data:      err.Error(),
+				RequestID: logger.RequestID(c),
+			})
+			return
+		}
+		profile.Password = string(hashedPassword)
+	}
+	dependent, err := h.familyUseCase.AddDependent(guardian, profile)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.AddDependent.04", "error", err)
+		h.failed(c, "FAILED_ADD_DEPENDENT", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Code:    http.StatusCreated,
+		Message: "DEPENDENT_ADDED",
+		Data:    dependent,
+	})
+}
+
+func (h handler) GetDependents(c *gin.Context) {
+	guardian, ok := h.currentUser(c, "Handler.GetDependents.01")
+	if !ok {
+		return
+	}
+	dependents, err := h.familyUseCase.GetDependents(guardian)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetDependents.02", "error", err)
+		h.failed(c, "FAILED_USECASE", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    dependents,
+	})
+}
+
+func (h handler) UpdateDependent(c *gin.Context) {
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.UpdateDependent.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	guardian, ok := h.currentUser(c, "Handler.UpdateDependent.02")
+	if !ok {
+		return
+	}
+	update := ProfileUpdate{
+		Name:          req.Name,
+		SpendingLimit: req.Spending_limit,
+	}
+	if req.Birthdate != nil {
+		birthdate, _ := time.Parse(dateLayout, *req.Birthdate)
+		update.Birthdate = &birthdate
+	}
+	dependent, err := h.familyUseCase.UpdateDependent(guardian, c.Param("id"), update)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.UpdateDependent.03", "error", err)
+		h.failed(c, "FAILED_UPDATE_DEPENDENT", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "DEPENDENT_UPDATED",
+		Data:    dependent,
+	})
+}
+
+func (h handler) currentUser(c *gin.Context, tag string) (entities.User, bool) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.accounts.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error(tag, "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return entities.User{}, false
+	}
+	return user, true
+}
+
+func (h handler) badRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, Response{
+		Code:      http.StatusBadRequest,
+		Message:   "BAD_REQUEST",
+		Data:      err.Error(),
+		RequestID: logger.RequestID(c),
+	})
+}
+
+func (h handler) failed(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrDependentNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNotAdult), errors.Is(err, ErrNestedDependent):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrBirthdateLocked):
+		status = http.StatusConflict
+	}
+	c.JSON(status, Response{
+		Code:      status,
+		Message:   message,
+		Data:      err.Error(),
+		RequestID: logger.RequestID(c),
+	})
+}
@@ -0,0 +1,12 @@
+package family
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+	UserRouter := r.Group("/user/dependents", middleware)
+	UserRouter.POST("", h.AddDependent)
+	UserRouter.GET("", h.GetDependents)
+	UserRouter.PATCH("/:id", h.UpdateDependent)
+}
@@ -0,0 +1,136 @@
+package family
+
+import (
+	"errors"
+	"time"
+
+	"movie-app-go/entities"
+
+	"github.com/google/uuid"
+)
+
+// adultAge is the age a guardian must have reached and a dependent must
+// still be under when added
+const adultAge = 18
+
+var (
+	ErrNotAdult          = errors.New("GUARDIAN_MUST_BE_ADULT")
+	ErrNotMinor          = errors.New("DEPENDENT_MUST_BE_MINOR")
+	ErrNestedDependent   = errors.New("DEPENDENT_CANNOT_BE_GUARDIAN")
+	ErrDependentNotFound = errors.New("DEPENDENT_NOT_FOUND")
+	ErrBirthdateLocked   = errors.New("BIRTHDATE_LOCKED")
+	ErrInvalidLimit      = errors.New("INVALID_SPENDING_LIMIT")
+)
+
+// Accounts is the part of the user use case dependent profiles are kept in
+type Accounts interface {
+	GetUser(username string) (entities.User, error)
+	Create(user entities.User) error
+	GetDependents(guardianID string) ([]entities.User, error)
+	UpdateUser(id string, update func(*entities.User) error) (entities.User, error)
+}
+
+// Profile describes a new dependent. Username and Password, already
+// hashed, are only set for dependents who log in themselves
+type Profile struct {
+	Name          string
+	Birthdate     time.Time
+	Username      string
+	Password      string
+	SpendingLimit int
+}
+
+// ProfileUpdate changes the fields that are not nil
+type ProfileUpdate struct {
+	Name          *string
+	Birthdate     *time.Time
+	SpendingLimit *int
+}
+
+type useCase struct {
+	accounts Accounts
+}
+
+type UseCaseInterface interface {
+	AddDependent(guardian entities.User, p Profile) (entities.User, error)
+	GetDependents(guardian entities.User) ([]entities.User, error)
+	UpdateDependent(guardian entities.User, id string, p ProfileUpdate) (entities.User, error)
+}
+
+func NewUseCase(accounts Accounts) UseCaseInterface {
+	return &useCase{
+		accounts: accounts,
+	}
+}
+
+func (usecase *useCase) AddDependent(guardian entities.User, p Profile) (entities.User, error) {
+	now := time.Now()
+	if guardian.Dependent() {
+		return entities.User{}, ErrNestedDependent
+	}
+	if guardian.AgeAt(now) < adultAge {
+		return entities.User{}, ErrNotAdult
+	}
+	if p.SpendingLimit < 0 {
+		return entities.User{}, ErrInvalidLimit
+	}
+	dependent := entities.User{
+		ID:             uuid.NewString(),
+		Username:       p.Username,
+		Password:       p.Password,
+		Name:           p.Name,
+		Birthdate:      p.Birthdate,
+		Role:           entities.RoleUser,
+		Guardian_id:    guardian.ID,
+		Spending_limit: p.SpendingLimit,
+		Created_at:     now,
+		Updated_at:     now,
+	}
+	if dependent.AgeAt(now) >= adultAge {
+		return entities.User{}, ErrNotMinor
+	}
+	if err := usecase.accounts.Create(dependent); err != nil {
+		return entities.User{}, err
+	}
+	return dependent, nil
+}
+
+func (usecase *useCase) GetDependents(guardian entities.User) ([]entities.User, error) {
+	dependents, err := usecase.accounts.GetDependents(guardian.ID)
+	if err != nil {
+		return nil, err
+	}
+	if dependents == nil {
+		dependents = []entities.User{}
+	}
+	return dependents, nil
+}
+
+// UpdateDependent lets the guardian rename a dependent, correct their date
+// of birth until staff have checked an ID, and change their spending limit
+func (usecase *useCase) UpdateDependent(guardian entities.User, id string, p ProfileUpdate) (entities.User, error) {
+	return usecase.accounts.UpdateUser(id, func(dependent *entities.User) error {
+		if dependent.Guardian_id != guardian.ID {
+			return ErrDependentNotFound
+		}
+		if p.Name != nil {
+			dependent.Name = *p.Name
+		}
+		if p.Birthdate != nil {
+			if dependent.ID_verified {
+				return ErrBirthdateLocked
+			}
+			dependent.Birthdate = *p.Birthdate
+			if dependent.AgeAt(time.Now()) >= adultAge || p.Birthdate.After(time.Now()) {
+				return ErrNotMinor
+			}
+		}
+		if p.SpendingLimit != nil {
+			if *p.SpendingLimit < 0 {
+				return ErrInvalidLimit
+			}
+			dependent.Spending_limit = *p.SpendingLimit
+		}
+		return nil
+	})
+}
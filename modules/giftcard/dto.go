@@ -0,0 +1,23 @@
+package giftcard
+
+import "time"
+
+type (
+	IssueRequest struct {
+		Amount     int       `json:"amount" binding:"required,min=1"`
+		Expires_at time.Time `json:"expires_at" binding:"required"`
+		Count      int       `json:"count" binding:"omitempty,min=1,max=100"`
+	}
+	ListRequest struct {
+		Status string `form:"status" binding:"omitempty,oneof=issued redeemed expired"`
+	}
+	RedeemRequest struct {
+		Code string `json:"code" binding:"required,max=32"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
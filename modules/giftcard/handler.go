@@ -0,0 +1,152 @@
+package giftcard
+
+import (
+	"errors"
+	"net/http"
+
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	giftCardUseCase UseCaseInterface
+	wallet          Wallet
+}
+
+type HandlerInterface interface {
+	IssueGiftCards(c *gin.Context)
+	GetGiftCards(c *gin.Context)
+	RedeemGiftCard(c *gin.Context)
+}
+
+func NewHandler(giftCardUseCase UseCaseInterface, wallet Wallet) HandlerInterface {
+	return &handler{
+		giftCardUseCase: giftCardUseCase,
+		wallet:          wallet,
+	}
+}
+
+func (h handler) IssueGiftCards(c *gin.Context) {
+	var req IssueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.IssueGiftCards.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if req.Count == 0 {
+		req.Count = 1
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	cards, err := h.giftCardUseCase.Issue(req.Amount, req.Expires_at, authInfo.(auth.AuthInfo).Username, req.Count)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.IssueGiftCards.02", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Code:    http.StatusCreated,
+		Message: "ISSUED_GIFT_CARDS",
+		Data:    cards,
+	})
+}
+
+func (h handler) GetGiftCards(c *gin.Context) {
+	var req ListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetGiftCards.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	cards, err := h.giftCardUseCase.GetAll(req.Status)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetGiftCards.02", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    cards,
+	})
+}
+
+func (h handler) RedeemGiftCard(c *gin.Context) {
+	var req RedeemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.RedeemGiftCard.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.wallet.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.RedeemGiftCard.02", "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	card, err := h.giftCardUseCase.Redeem(req.Code, &user, c.ClientIP())
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.RedeemGiftCard.03", "error", err)
+
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrTooManyAttempts) {
+			status = http.StatusTooManyRequests
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_REDEEM",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS_REDEEM",
+		Data: gin.H{
+			"amount":  card.Amount,
+			"balance": user.Balance,
+		},
+	})
+}
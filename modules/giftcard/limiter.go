@@ -0,0 +1,92 @@
+package giftcard
+
+import (
+	"sync"
+	"time"
+)
+
+// attemptLimiter counts failed redemptions per key within a sliding window
+type attemptLimiter struct {
+	mu        sync.Mutex
+	failures  map[string][]time.Time
+	max       int
+	window    time.Duration
+	lastSweep time.Time
+}
+
+func newAttemptLimiter(max int, window time.Duration) *attemptLimiter {
+	return &attemptLimiter{
+		failures: make(map[string][]time.Time),
+		max:      max,
+		window:   window,
+	}
+}
+
+// Attempt takes one attempt for all the keys, counted as failed until
+// Forgive takes it back. It reports false and counts nothing once any of
+// the keys used up its failures, so concurrent guesses never get past the
+// limit between checking and counting
+func (l *attemptLimiter) Attempt(at time.Time, keys ...string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(at)
+	for _, key := range keys {
+		if len(l.prune(key, at)) >= l.max {
+			return false
+		}
+	}
+	for _, key := range keys {
+		l.failures[key] = append(l.failures[key], at)
+	}
+	return true
+}
+
+// Forgive takes back the attempt made at the given time, for attempts that
+// turned out not to be guesses
+func (l *attemptLimiter) Forgive(at time.Time, keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		failures := l.failures[key]
+		for i := len(failures) - 1; i >= 0; i-- {
+			if failures[i].Equal(at) {
+				failures = append(failures[:i], failures[i+1:]...)
+				break
+			}
+		}
+		if len(failures) == 0 {
+			delete(l.failures, key)
+			continue
+		}
+		l.failures[key] = failures
+	}
+}
+
+// sweep drops the keys whose failures all left the window, at most once
+// per window, so clients that stop guessing are not remembered forever
+func (l *attemptLimiter) sweep(at time.Time) {
+	if at.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = at
+	for key := range l.failures {
+		l.prune(key, at)
+	}
+}
+
+func (l *attemptLimiter) prune(key string, at time.Time) []time.Time {
+	kept := l.failures[key][:0]
+	for _, t := range l.failures[key] {
+		if at.Sub(t) < l.window {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == 0 {
+		delete(l.failures, key)
+		return nil
+	}
+	l.failures[key] = kept
+	return kept
+}
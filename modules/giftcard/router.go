@@ -0,0 +1,14 @@
+package giftcard
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc, adminOnly gin.HandlerFunc) {
+	AdminRouter := r.Group("/admin/gift-cards", middleware, adminOnly)
+	AdminRouter.POST("", h.IssueGiftCards)
+	AdminRouter.GET("", h.GetGiftCards)
+
+	UserRouter := r.Group("/user")
+	UserRouter.POST("/redeem-gift-card", middleware, h.RedeemGiftCard)
+}
@@ -0,0 +1,199 @@
+package giftcard
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Codes carry 80 random bits, written as four groups of four characters
+const codeBytes = 10
+
+const (
+	maxFailedAttempts = 5
+	failureWindow     = 15 * time.Minute
+)
+
+// Gift card listing filters
+const (
+	StatusIssued   = "issued"
+	StatusRedeemed = "redeemed"
+	StatusExpired  = "expired"
+)
+
+var (
+	ErrInvalidCode      = errors.New("GIFT_CARD_INVALID")
+	ErrAlreadyRedeemed  = errors.New("GIFT_CARD_ALREADY_REDEEMED")
+	ErrExpired          = errors.New("GIFT_CARD_EXPIRED")
+	ErrTooManyAttempts  = errors.New("TOO_MANY_ATTEMPTS")
+	ErrInvalidExpiry    = errors.New("INVALID_EXPIRY")
+	ErrNonPositiveValue = errors.New("NON_POSITIVE_VALUE")
+)
+
+// Wallet is the part of the user use case gift cards credit through
+type Wallet interface {
+	GetUser(username string) (entities.User, error)
+	TopUp(u *entities.User, n int) error
+}
+
+// IssuedCard pairs a stored card with its code, which is never stored
+type IssuedCard struct {
+	entities.GiftCard
+	Code string `json:"code"`
+}
+
+type useCase struct {
+	// mu makes checking and marking a card redeemed one step
+	mu           sync.Mutex
+	giftCardRepo repositories.GiftCardRepositoryInterface
+	wallet       Wallet
+	limiter      *attemptLimiter
+}
+
+type UseCaseInterface interface {
+	Issue(amount int, expiresAt time.Time, issuedBy string, count int) ([]IssuedCard, error)
+	GetAll(status string) ([]entities.GiftCard, error)
+	Redeem(code string, u *entities.User, clientIP string) (entities.GiftCard, error)
+}
+
+func NewUseCase(giftCardRepo repositories.GiftCardRepositoryInterface, wallet Wallet) UseCaseInterface {
+	return &useCase{
+		giftCardRepo: giftCardRepo,
+		wallet:       wallet,
+		limiter:      newAttemptLimiter(maxFailedAttempts, failureWindow),
+	}
+}
+
+func (usecase *useCase) Issue(amount int, expiresAt time.Time, issuedBy string, count int) ([]IssuedCard, error) {
+	if amount <= 0 || count <= 0 {
+		return nil, ErrNonPositiveValue
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	cards := make([]IssuedCard, 0, count)
+	for i := 0; i < count; i++ {
+		code, err := generateCode()
+		if err != nil {
+			return nil, err
+		}
+		UUID, err := uuid.NewRandom()
+		if err != nil {
+			return nil, err
+		}
+		normalized := normalizeCode(code)
+		card := entities.GiftCard{
+			ID:          UUID.String(),
+			Code_hash:   hashCode(normalized),
+			Code_suffix: normalized[len(normalized)-4:],
+			Amount:      amount,
+			Expires_at:  expiresAt,
+			Issued_by:   issuedBy,
+			Created_at:  time.Now(),
+		}
+		if err := usecase.giftCardRepo.Create(card); err != nil {
+			return nil, err
+		}
+		cards = append(cards, IssuedCard{GiftCard: card, Code: code})
+	}
+	return cards, nil
+}
+
+func (usecase *useCase) GetAll(status string) ([]entities.GiftCard, error) {
+	cards, err := usecase.giftCardRepo.ReadAll()
+	if err != nil || status == "" {
+		return cards, err
+	}
+	now := time.Now()
+	filtered := make([]entities.GiftCard, 0)
+	for _, card := range cards {
+		if cardStatus(card, now) == status {
+			filtered = append(filtered, card)
+		}
+	}
+	return filtered, nil
+}
+
+// Redeem credits the card to the user's balance. Failed attempts are
+// counted per user and per client IP to slow down code guessing
+func (usecase *useCase) Redeem(code string, u *entities.User, clientIP string) (entities.GiftCard, error) {
+	now := time.Now()
+	keys := []string{"user:" + u.ID, "ip:" + clientIP}
+	if !usecase.limiter.Attempt(now, keys...) {
+		return entities.GiftCard{}, ErrTooManyAttempts
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	card, err := usecase.giftCardRepo.ReadByHash(hashCode(normalizeCode(code)))
+	if err != nil {
+		return entities.GiftCard{}, ErrInvalidCode
+	}
+	if card.Redeemed() {
+		return entities.GiftCard{}, ErrAlreadyRedeemed
+	}
+	// Anything past here knew a real code, it was no guess
+	usecase.limiter.Forgive(now, keys...)
+	if now.After(card.Expires_at) {
+		return entities.GiftCard{}, ErrExpired
+	}
+
+	card.Redeemed_by = u.ID
+	card.Redeemed_at = now
+	if err := usecase.giftCardRepo.Update(card); err != nil {
+		return entities.GiftCard{}, err
+	}
+	if err := usecase.wallet.TopUp(u, card.Amount); err != nil {
+		card.Redeemed_by = ""
+		card.Redeemed_at = time.Time{}
+		usecase.giftCardRepo.Update(card)
+		return entities.GiftCard{}, err
+	}
+	return card, nil
+}
+
+func cardStatus(card entities.GiftCard, at time.Time) string {
+	switch {
+	case card.Redeemed():
+		return StatusRedeemed
+	case at.After(card.Expires_at):
+		return StatusExpired
+	}
+	return StatusIssued
+}
+
+func generateCode() (string, error) {
+	b := make([]byte, codeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeCode accepts codes typed in lower case, with or without dashes
+func normalizeCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func hashCode(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package giftcard
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+)
+
+// fakeWallet credits balances in memory
+type fakeWallet struct {
+	mu       sync.Mutex
+	credited int
+}
+
+func (w *fakeWallet) GetUser(username string) (entities.User, error) {
+	return entities.User{ID: "u-" + username, Username: username}, nil
+}
+
+func (w *fakeWallet) TopUp(u *entities.User, n int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.credited += n
+	u.Balance += n
+	return nil
+}
+
+func TestRedeemLimitsBurstsOfGuesses(t *testing.T) {
+	tests := []struct {
+		name string
+		// user picks the account each guess is made from, all guesses come
+		// from the same client IP
+		user func(i int) string
+	}{
+		{name: "one user", user: func(int) string { return "mallory" }},
+		{name: "one IP, many users", user: func(i int) string { return fmt.Sprintf("user-%d", i) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := NewUseCase(repositories.NewGiftCardRepository(nil), &fakeWallet{})
+			const guesses = 64
+			var (
+				wg      sync.WaitGroup
+				mu      sync.Mutex
+				checked int
+			)
+			for i := 0; i < guesses; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					u := entities.User{ID: tt.user(i)}
+					_, err := usecase.Redeem(fmt.Sprintf("GUESS-%04d", i), &u, "203.0.113.7")
+					if errors.Is(err, ErrInvalidCode) {
+						mu.Lock()
+						checked++
+						mu.Unlock()
+					}
+				}(i)
+			}
+			wg.Wait()
+			if checked != maxFailedAttempts {
+				t.Errorf("%d guesses were checked, want %d", checked, maxFailedAttempts)
+			}
+		})
+	}
+}
+
+func TestRedeemDoesNotCountValidCodes(t *testing.T) {
+	wallet := &fakeWallet{}
+	usecase := NewUseCase(repositories.NewGiftCardRepository(nil), wallet)
+	cards, err := usecase.Issue(1000, time.Now().Add(time.Hour), "admin", maxFailedAttempts+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := entities.User{ID: "u-alice"}
+	for _, card := range cards {
+		if _, err := usecase.Redeem(card.Code, &u, "203.0.113.7"); err != nil {
+			t.Fatalf("redeeming %s: %v", card.Code, err)
+		}
+	}
+	if want := 1000 * len(cards); wallet.credited != want {
+		t.Errorf("credited %d, want %d", wallet.credited, want)
+	}
+}
+
+func TestLimiterForgetsIdleKeys(t *testing.T) {
+	limiter := newAttemptLimiter(2, time.Minute)
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		limiter.Attempt(start, fmt.Sprintf("ip:%d", i))
+	}
+	limiter.Attempt(start.Add(2*time.Minute), "ip:fresh")
+	if len(limiter.failures) != 1 {
+		t.Errorf("limiter still remembers %d keys, want only the fresh one", len(limiter.failures))
+	}
+}
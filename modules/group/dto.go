@@ -0,0 +1,39 @@
+package group
+
+import "movie-app-go/entities"
+
+type (
+	CreateRequest struct {
+		Movie_id   int            `json:"movie_id" binding:"required"`
+		Party_size int            `json:"party_size" binding:"required,min=2"`
+		Contact    ContactRequest `json:"contact" binding:"required"`
+		Note       string         `json:"note" binding:"max=500"`
+	}
+	ContactRequest struct {
+		Name  string `json:"name" binding:"required,max=100"`
+		Email string `json:"email" binding:"required,email"`
+		Phone string `json:"phone" binding:"max=30"`
+	}
+	ApproveRequest struct {
+		Seats []entities.Seat `json:"seats"`
+	}
+	RejectRequest struct {
+		Note string `json:"note" binding:"required,max=500"`
+	}
+	SplitRequest struct {
+		Shares []ShareItem `json:"shares" binding:"required,min=1,dive"`
+	}
+	ShareItem struct {
+		Username string `json:"username" binding:"required"`
+		Seats    int    `json:"seats" binding:"required,min=1"`
+	}
+	ListRequest struct {
+		Status string `form:"status" binding:"omitempty,oneof=requested approved rejected confirmed cancelled expired"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
@@ -0,0 +1,268 @@
+package group
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	groupUseCase UseCaseInterface
+	booking      Booking
+}
+
+type HandlerInterface interface {
+	RequestGroupBooking(c *gin.Context)
+	GetMyGroupBookings(c *gin.Context)
+	SplitPayment(c *gin.Context)
+	PayShare(c *gin.Context)
+	CancelGroupBooking(c *gin.Context)
+	GetGroupBookings(c *gin.Context)
+	ApproveGroupBooking(c *gin.Context)
+	RejectGroupBooking(c *gin.Context)
+}
+
+func NewHandler(groupUseCase UseCaseInterface, booking Booking) HandlerInterface {
+	return &handler{
+		groupUseCase: groupUseCase,
+		booking:      booking,
+	}
+}
+
+func (h handler) RequestGroupBooking(c *gin.Context) {
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.RequestGroupBooking.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.RequestGroupBooking.02")
+	if !ok {
+		return
+	}
+	contact := entities.GroupContact{
+		Name:  req.Contact.Name,
+		Email: req.Contact.Email,
+		Phone: req.Contact.Phone,
+	}
+	group, err := h.groupUseCase.Request(user, req.Movie_id, req.Party_size, contact, req.Note)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.RequestGroupBooking.03", "error", err)
+		h.failed(c, "FAILED_REQUEST", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Code:    http.StatusCreated,
+		Message: "GROUP_BOOKING_REQUESTED",
+		Data:    group,
+	})
+}
+
+func (h handler) GetMyGroupBookings(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.GetMyGroupBookings.01")
+	if !ok {
+		return
+	}
+	groups, err := h.groupUseCase.GetForUser(user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetMyGroupBookings.02", "error", err)
+		h.failed(c, "FAILED_USECASE", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    groups,
+	})
+}
+
+func (h handler) SplitPayment(c *gin.Context) {
+	var req SplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.SplitPayment.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.SplitPayment.02")
+	if !ok {
+		return
+	}
+	shares := make([]ShareRequest, 0, len(req.Shares))
+	for _, share := range req.Shares {
+		shares = append(shares, ShareRequest{Username: share.Username, Seats: share.Seats})
+	}
+	group, err := h.groupUseCase.Split(user, c.Param("id"), shares)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.SplitPayment.03", "error", err)
+		h.failed(c, "FAILED_SPLIT", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "PAYMENT_SPLIT",
+		Data:    group,
+	})
+}
+
+func (h handler) PayShare(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.PayShare.01")
+	if !ok {
+		return
+	}
+	group, err := h.groupUseCase.Pay(user, c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.PayShare.02", "error", err)
+		h.failed(c, "FAILED_PAYMENT", err)
+		return
+	}
+
+	message := "SHARE_PAID"
+	if group.Status == entities.GroupConfirmed {
+		message = "GROUP_BOOKING_CONFIRMED"
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: message,
+		Data:    group,
+	})
+}
+
+func (h handler) CancelGroupBooking(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.CancelGroupBooking.01")
+	if !ok {
+		return
+	}
+	group, err := h.groupUseCase.Cancel(user, c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.CancelGroupBooking.02", "error", err)
+		h.failed(c, "FAILED_CANCEL", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "GROUP_BOOKING_CANCELLED",
+		Data:    group,
+	})
+}
+
+func (h handler) GetGroupBookings(c *gin.Context) {
+	var req ListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetGroupBookings.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	groups, err := h.groupUseCase.GetAll(req.Status)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetGroupBookings.02", "error", err)
+		h.failed(c, "FAILED_USECASE", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    groups,
+	})
+}
+
+func (h handler) ApproveGroupBooking(c *gin.Context) {
+	var req ApproveRequest
+	// The body is optional, without seats the best block is picked
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.FromContext(c).Error("Handler.ApproveGroupBooking.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	group, err := h.groupUseCase.Approve(c.Param("id"), authInfo.(auth.AuthInfo).Username, req.Seats)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.ApproveGroupBooking.02", "error", err)
+		h.failed(c, "FAILED_APPROVE", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "GROUP_BOOKING_APPROVED",
+		Data:    group,
+	})
+}
+
+func (h handler) RejectGroupBooking(c *gin.Context) {
+	var req RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.RejectGroupBooking.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	group, err := h.groupUseCase.Reject(c.Param("id"), authInfo.(auth.AuthInfo).Username, req.Note)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.RejectGroupBooking.02", "error", err)
+		h.failed(c, "FAILED_REJECT", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "GROUP_BOOKING_REJECTED",
+		Data:    group,
+	})
+}
+
+func (h handler) currentUser(c *gin.Context, tag string) (entities.User, bool) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.booking.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error(tag, "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return entities.User{}, false
+	}
+	return user, true
+}
+
+func (h handler) badRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, Response{
+		Code:      http.StatusBadRequest,
+		Message:   "BAD_REQUEST",
+		Data:      err.Error(),
+		RequestID: logger.RequestID(c),
+	})
+}
+
+func (h handler) failed(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrGroupNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNotPermitted), errors.Is(err, ErrNotOnSale):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrInvalidStatus), errors.Is(err, ErrAlreadyPaid),
+		errors.Is(err, ErrReservationLapsed), errors.Is(err, ErrShowStarted),
+		errors.Is(err, ErrBookingFailed):
+		status = http.StatusConflict
+	}
+	c.JSON(status, Response{
+		Code:      status,
+		Message:   message,
+		Data:      err.Error(),
+		RequestID: logger.RequestID(c),
+	})
+}
@@ -0,0 +1,19 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc, staffOnly gin.HandlerFunc) {
+	UserRouter := r.Group("/user/group-bookings", middleware)
+	UserRouter.POST("", h.RequestGroupBooking)
+	UserRouter.GET("", h.GetMyGroupBookings)
+	UserRouter.POST("/:id/split", h.SplitPayment)
+	UserRouter.POST("/:id/pay", h.PayShare)
+	UserRouter.POST("/:id/cancel", h.CancelGroupBooking)
+
+	StaffRouter := r.Group("/staff/group-bookings", middleware, staffOnly)
+	StaffRouter.GET("", h.GetGroupBookings)
+	StaffRouter.POST("/:id/approve", h.ApproveGroupBooking)
+	StaffRouter.POST("/:id/reject", h.RejectGroupBooking)
+}
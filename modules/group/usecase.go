@@ -0,0 +1,632 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/notify"
+	"movie-app-go/modules/seating"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGroupNotFound     = errors.New("GROUP_BOOKING_NOT_FOUND")
+	ErrInvalidStatus     = errors.New("GROUP_BOOKING_INVALID_STATUS")
+	ErrPartySize         = errors.New("INVALID_PARTY_SIZE")
+	ErrNotPermitted      = errors.New("NOT_PERMITTED")
+	ErrNotOnSale         = errors.New("NOT_ON_SALE")
+	ErrShowStarted       = errors.New("SHOW_ALREADY_STARTED")
+	ErrSeatCount         = errors.New("SEAT_COUNT_MISMATCH")
+	ErrInvalidShares     = errors.New("INVALID_SHARES")
+	ErrAlreadyPaid       = errors.New("SHARE_ALREADY_PAID")
+	ErrNoShare           = errors.New("NO_SHARE_TO_PAY")
+	ErrReservationLapsed = errors.New("RESERVATION_EXPIRED")
+	ErrBookingFailed     = errors.New("GROUP_BOOKING_FAILED")
+)
+
+// Limits bound group bookings. Approved blocks stay held for HoldDuration,
+// never past the showtime
+type Limits struct {
+	MaxPartySize int
+	HoldDuration time.Duration
+}
+
+// ShareRequest assigns a number of the block's seats to one payer
+type ShareRequest struct {
+	Username string
+	Seats    int
+}
+
+// Booking is the part of the user use case group bookings work through
+type Booking interface {
+	GetUser(username string) (entities.User, error)
+	GetMovie(id int) (entities.Movie, error)
+	NotPermitted(m entities.Movie, u entities.User) bool
+	SalesOpen(m entities.Movie) time.Time
+	QuotePrice(m entities.Movie, seats []entities.Seat) entities.PriceBreakdown
+	ReserveSeats(seat []entities.Seat, m *entities.Movie, holder string, until time.Time) ([]entities.Seat, error)
+	ReleaseReserved(seat []entities.Seat, m *entities.Movie, holder string) ([]entities.Seat, error)
+	BookSeats(seat []entities.Seat, m *entities.Movie, holder string) ([]entities.Seat, error)
+	FreeSeats(seat []entities.Seat, m *entities.Movie) []entities.Seat
+	Withdraw(u *entities.User, n int) error
+	TopUp(u *entities.User, n int) error
+	BuyTicket(u entities.User, t entities.Ticket) error
+}
+
+type useCase struct {
+	// mu serialises every state change of a group booking
+	mu        sync.Mutex
+	groupRepo repositories.GroupBookingRepositoryInterface
+	booking   Booking
+	notifier  notify.Notifier
+	limits    Limits
+}
+
+type UseCaseInterface interface {
+	Request(u entities.User, movieID, partySize int, contact entities.GroupContact, note string) (entities.GroupBooking, error)
+	Approve(id, staff string, seats []entities.Seat) (entities.GroupBooking, error)
+	Reject(id, staff, note string) (entities.GroupBooking, error)
+	Split(u entities.User, id string, shares []ShareRequest) (entities.GroupBooking, error)
+	Pay(u entities.User, id string) (entities.GroupBooking, error)
+	Cancel(u entities.User, id string) (entities.GroupBooking, error)
+	GetForUser(u entities.User) ([]entities.GroupBooking, error)
+	GetAll(status string) ([]entities.GroupBooking, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+func NewUseCase(groupRepo repositories.GroupBookingRepositoryInterface, booking Booking, notifier notify.Notifier, limits Limits) UseCaseInterface {
+	return &useCase{
+		groupRepo: groupRepo,
+		booking:   booking,
+		notifier:  notifier,
+		limits:    limits,
+	}
+}
+
+func (usecase *useCase) Request(u entities.User, movieID, partySize int, contact entities.GroupContact, note string) (entities.GroupBooking, error) {
+	if partySize < 2 || partySize > usecase.limits.MaxPartySize {
+		return entities.GroupBooking{}, ErrPartySize
+	}
+	movie, err := usecase.booking.GetMovie(movieID)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+	if partySize > len(movie.Seats) {
+		return entities.GroupBooking{}, ErrPartySize
+	}
+	if usecase.booking.NotPermitted(movie, u) {
+		return entities.GroupBooking{}, ErrNotPermitted
+	}
+	now := time.Now()
+	if !now.Before(movie.Showtime) {
+		return entities.GroupBooking{}, ErrShowStarted
+	}
+	if opens := usecase.booking.SalesOpen(movie); now.Before(opens) {
+		return entities.GroupBooking{}, fmt.Errorf("%w: tickets go on sale %s", ErrNotOnSale, opens.Format(time.RFC3339))
+	}
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+
+	group := entities.GroupBooking{
+		ID:                 UUID.String(),
+		MovieID:            movie.ID,
+		Organizer_id:       u.ID,
+		Organizer_username: u.Username,
+		PartySize:          partySize,
+		Contact:            contact,
+		Note:               note,
+		Status:             entities.GroupRequested,
+		Created_at:         now,
+		Updated_at:         now,
+	}
+	if err := usecase.groupRepo.Create(group); err != nil {
+		return entities.GroupBooking{}, err
+	}
+	return group, nil
+}
+
+// Approve reserves a block for the group, the seats staff picked or the
+// best ones left. The price is fixed at approval and the organizer owes
+// all of it until they split the payment
+func (usecase *useCase) Approve(id, staff string, seats []entities.Seat) (entities.GroupBooking, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	group, err := usecase.read(id, entities.GroupRequested)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+	movie, err := usecase.booking.GetMovie(group.MovieID)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+	now := time.Now()
+	if !now.Before(movie.Showtime) {
+		return entities.GroupBooking{}, ErrShowStarted
+	}
+	if len(seats) == 0 {
+		suggestion, err := seating.Suggest(movie.Seats, seating.Options{PartySize: group.PartySize}, func(s entities.Seat) bool {
+			return s.Status(now) == entities.SeatAvailable
+		})
+		if err != nil {
+			return entities.GroupBooking{}, err
+		}
+		seats = suggestion.Seats
+	}
+	if len(seats) != group.PartySize {
+		return entities.GroupBooking{}, ErrSeatCount
+	}
+
+	until := now.Add(usecase.limits.HoldDuration)
+	if movie.Showtime.Before(until) {
+		until = movie.Showtime
+	}
+	held, err := usecase.booking.ReserveSeats(seats, &movie, group.Holder(), until)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+
+	group.Status = entities.GroupApproved
+	group.Seats = labels(held)
+	group.Breakdown = usecase.booking.QuotePrice(movie, held)
+	group.Shares = []entities.GroupShare{{
+		UserID:   group.Organizer_id,
+		Username: group.Organizer_username,
+		Seats:    group.Seats,
+		Amount:   group.Breakdown.Total,
+	}}
+	group.Reviewed_by = staff
+	group.Reviewed_at = now
+	group.Hold_expires_at = until
+	group.Updated_at = now
+	if err := usecase.groupRepo.Update(group); err != nil {
+		usecase.booking.ReleaseReserved(held, &movie, group.Holder())
+		return entities.GroupBooking{}, err
+	}
+	usecase.send(group.Organizer_id, group.Organizer_username, notify.Notification{
+		Kind:    notify.GroupApproved,
+		Subject: fmt.Sprintf("Group booking approved for %s", movie.Title),
+		Message: fmt.Sprintf("%d seats are reserved until %s, pay %d to confirm them", group.PartySize, until.Format("2006-01-02 15:04"), group.Breakdown.Total),
+		Data:    map[string]any{"group_id": group.ID, "seats": group.Seats},
+	})
+	return group, nil
+}
+
+func (usecase *useCase) Reject(id, staff, note string) (entities.GroupBooking, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	group, err := usecase.read(id, entities.GroupRequested)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+	now := time.Now()
+	group.Status = entities.GroupRejected
+	group.Reviewed_by = staff
+	group.Review_note = note
+	group.Reviewed_at = now
+	group.Updated_at = now
+	if err := usecase.groupRepo.Update(group); err != nil {
+		return entities.GroupBooking{}, err
+	}
+	usecase.send(group.Organizer_id, group.Organizer_username, notify.Notification{
+		Kind:    notify.GroupRejected,
+		Subject: "Group booking rejected",
+		Message: note,
+		Data:    map[string]any{"group_id": group.ID},
+	})
+	return group, nil
+}
+
+// Split divides the block between invited users, each paying for their own
+// seats. Every invitee must be old enough for the movie
+func (usecase *useCase) Split(u entities.User, id string, shares []ShareRequest) (entities.GroupBooking, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	group, err := usecase.read(id, entities.GroupApproved)
+	if err != nil || group.Organizer_id != u.ID {
+		return entities.GroupBooking{}, ErrGroupNotFound
+	}
+	for _, share := range group.Shares {
+		if share.Paid {
+			return entities.GroupBooking{}, ErrAlreadyPaid
+		}
+	}
+	movie, err := usecase.booking.GetMovie(group.MovieID)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+
+	prices := make(map[string]int, len(group.Breakdown.Items))
+	for _, item := range group.Breakdown.Items {
+		prices[item.Seat] = item.Price
+	}
+	seen := make(map[string]bool)
+	next := 0
+	split := make([]entities.GroupShare, 0, len(shares))
+	for _, share := range shares {
+		if share.Seats < 1 || seen[share.Username] || next+share.Seats > len(group.Seats) {
+			return entities.GroupBooking{}, ErrInvalidShares
+		}
+		seen[share.Username] = true
+		member, err := usecase.booking.GetUser(share.Username)
+		if err != nil {
+			return entities.GroupBooking{}, fmt.Errorf("%w: unknown user %s", ErrInvalidShares, share.Username)
+		}
+		if usecase.booking.NotPermitted(movie, member) {
+			return entities.GroupBooking{}, fmt.Errorf("%w: %s", ErrNotPermitted, share.Username)
+		}
+		part := entities.GroupShare{
+			UserID:   member.ID,
+			Username: member.Username,
+			Seats:    group.Seats[next : next+share.Seats],
+		}
+		for _, seat := range part.Seats {
+			part.Amount += prices[seat]
+		}
+		next += share.Seats
+		split = append(split, part)
+	}
+	if next != len(group.Seats) {
+		return entities.GroupBooking{}, ErrSeatCount
+	}
+
+	group.Shares = split
+	group.Updated_at = time.Now()
+	if err := usecase.groupRepo.Update(group); err != nil {
+		return entities.GroupBooking{}, err
+	}
+	for _, share := range split {
+		if share.UserID == group.Organizer_id {
+			continue
+		}
+		usecase.send(share.UserID, share.Username, notify.Notification{
+			Kind:    notify.GroupInvited,
+			Subject: fmt.Sprintf("%s invited you to see %s", group.Organizer_username, movie.Title),
+			Message: fmt.Sprintf("Pay %d for seats %v before %s", share.Amount, share.Seats, group.Hold_expires_at.Format("2006-01-02 15:04")),
+			Data:    map[string]any{"group_id": group.ID, "seats": share.Seats, "amount": share.Amount},
+		})
+	}
+	return group, nil
+}
+
+// Pay takes the caller's share from their balance. Tickets are issued once
+// the last share is paid, until then the block stays reserved
+func (usecase *useCase) Pay(u entities.User, id string) (entities.GroupBooking, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	group, err := usecase.read(id, entities.GroupApproved)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+	now := time.Now()
+	if !now.Before(group.Hold_expires_at) {
+		return entities.GroupBooking{}, ErrReservationLapsed
+	}
+	i := -1
+	for j, share := range group.Shares {
+		if share.UserID == u.ID {
+			i = j
+		}
+	}
+	if i < 0 {
+		return entities.GroupBooking{}, ErrNoShare
+	}
+	if group.Shares[i].Paid {
+		return entities.GroupBooking{}, ErrAlreadyPaid
+	}
+
+	if err := usecase.booking.Withdraw(&u, group.Shares[i].Amount); err != nil {
+		return entities.GroupBooking{}, err
+	}
+	shares := make([]entities.GroupShare, len(group.Shares))
+	copy(shares, group.Shares)
+	shares[i].Paid = true
+	shares[i].Paid_at = now
+	group.Shares = shares
+	group.Updated_at = now
+	if err := usecase.groupRepo.Update(group); err != nil {
+		usecase.booking.TopUp(&u, shares[i].Amount)
+		return entities.GroupBooking{}, err
+	}
+
+	for _, share := range group.Shares {
+		if !share.Paid {
+			return group, nil
+		}
+	}
+	return usecase.confirm(group, now)
+}
+
+// Cancel withdraws the request or gives up the reserved block, refunding
+// whatever was paid so far
+func (usecase *useCase) Cancel(u entities.User, id string) (entities.GroupBooking, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	group, err := usecase.groupRepo.Read(id)
+	if err != nil || group.Organizer_id != u.ID {
+		return entities.GroupBooking{}, ErrGroupNotFound
+	}
+	if group.Status != entities.GroupRequested && group.Status != entities.GroupApproved {
+		return entities.GroupBooking{}, ErrInvalidStatus
+	}
+	return usecase.unwind(group, entities.GroupCancelled, time.Now())
+}
+
+// GetForUser lists the group bookings the user organizes or has a share in
+func (usecase *useCase) GetForUser(u entities.User) ([]entities.GroupBooking, error) {
+	groups, err := usecase.groupRepo.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	mine := make([]entities.GroupBooking, 0)
+	for _, group := range groups {
+		if group.Organizer_id == u.ID {
+			mine = append(mine, group)
+			continue
+		}
+		for _, share := range group.Shares {
+			if share.UserID == u.ID {
+				mine = append(mine, group)
+				break
+			}
+		}
+	}
+	return mine, nil
+}
+
+func (usecase *useCase) GetAll(status string) ([]entities.GroupBooking, error) {
+	groups, err := usecase.groupRepo.ReadAll()
+	if err != nil || status == "" {
+		return groups, err
+	}
+	filtered := make([]entities.GroupBooking, 0)
+	for _, group := range groups {
+		if group.Status == status {
+			filtered = append(filtered, group)
+		}
+	}
+	return filtered, nil
+}
+
+// Run releases blocks whose reservation lapsed before every share was paid
+func (usecase *useCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			usecase.expire(now)
+		}
+	}
+}
+
+func (usecase *useCase) expire(now time.Time) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	groups, err := usecase.groupRepo.ReadAll()
+	if err != nil {
+		slog.Error("UseCase.Group.Expire.01", "error", err)
+		return
+	}
+	for _, group := range groups {
+		if group.Status != entities.GroupApproved || now.Before(group.Hold_expires_at) {
+			continue
+		}
+		if _, err := usecase.unwind(group, entities.GroupExpired, now); err != nil {
+			slog.Error("UseCase.Group.Expire.02", "error", err, "group_id", group.ID)
+			continue
+		}
+		for _, share := range group.Shares {
+			usecase.send(share.UserID, share.Username, notify.Notification{
+				Kind:    notify.GroupExpired,
+				Subject: "Group booking expired",
+				Message: "The reserved seats were released before every share was paid, payments were refunded",
+				Data:    map[string]any{"group_id": group.ID},
+			})
+		}
+	}
+}
+
+// confirm books the block and issues one ticket per share. When the block
+// cannot be booked every share is refunded, a share whose ticket cannot be
+// issued is refunded and its seats freed
+func (usecase *useCase) confirm(group entities.GroupBooking, now time.Time) (entities.GroupBooking, error) {
+	movie, err := usecase.booking.GetMovie(group.MovieID)
+	if err != nil {
+		return entities.GroupBooking{}, err
+	}
+	held, err := usecase.booking.BookSeats(blockSeats(movie, group.Seats), &movie, group.Holder())
+	if err != nil {
+		slog.Error("UseCase.Group.Confirm.03", "error", err, "group_id", group.ID)
+		if _, err := usecase.unwind(group, entities.GroupCancelled, now); err != nil {
+			return entities.GroupBooking{}, err
+		}
+		return entities.GroupBooking{}, fmt.Errorf("%w: %v", ErrBookingFailed, err)
+	}
+	booked := make(map[string]entities.Seat, len(held))
+	for _, seat := range held {
+		booked[fmt.Sprintf("%s%d", seat.Row, seat.Number)] = seat
+	}
+
+	shares := make([]entities.GroupShare, len(group.Shares))
+	copy(shares, group.Shares)
+	for i, share := range shares {
+		var seats []entities.Seat
+		for _, label := range share.Seats {
+			seats = append(seats, booked[label])
+		}
+		member, err := usecase.booking.GetUser(share.Username)
+		if err != nil {
+			// Nobody to refund, the share stays paid without a ticket for
+			// staff to settle
+			slog.Error("UseCase.Group.Confirm.01", "error", err, "group_id", group.ID, "username", share.Username)
+			usecase.booking.FreeSeats(seats, &movie)
+			continue
+		}
+		UUID, err := uuid.NewRandom()
+		if err == nil {
+			err = usecase.booking.BuyTicket(member, entities.Ticket{
+				ID:         UUID.String(),
+				UserID:     member.ID,
+				Movie:      movie,
+				Seats:      seats,
+				Cost:       share.Amount,
+				Breakdown:  shareBreakdown(group.Breakdown, share.Seats),
+				Created_At: now,
+				Updated_At: now,
+			})
+		}
+		if err != nil {
+			slog.Error("UseCase.Group.Confirm.02", "error", err, "group_id", group.ID)
+			shares[i] = usecase.refund(group, share, member, seats, &movie)
+			continue
+		}
+		shares[i].TicketID = UUID.String()
+		usecase.send(member.ID, member.Username, notify.Notification{
+			Kind:    notify.GroupConfirmed,
+			Subject: fmt.Sprintf("Your tickets for %s", movie.Title),
+			Message: fmt.Sprintf("Group booking confirmed, seats %v", share.Seats),
+			Data:    map[string]any{"group_id": group.ID, "ticket_id": shares[i].TicketID},
+		})
+	}
+
+	group.Shares = shares
+	group.Status = entities.GroupConfirmed
+	group.Updated_at = now
+	if err := usecase.groupRepo.Update(group); err != nil {
+		return entities.GroupBooking{}, err
+	}
+	return group, nil
+}
+
+// refund pays back a share whose ticket could not be issued and frees its
+// seats
+func (usecase *useCase) refund(group entities.GroupBooking, share entities.GroupShare, member entities.User, seats []entities.Seat, movie *entities.Movie) entities.GroupShare {
+	usecase.booking.FreeSeats(seats, movie)
+	if err := usecase.booking.TopUp(&member, share.Amount); err != nil {
+		slog.Error("UseCase.Group.Refund.01", "error", err, "group_id", group.ID, "username", share.Username)
+		return share
+	}
+	share.Paid = false
+	share.Paid_at = time.Time{}
+	usecase.send(member.ID, member.Username, notify.Notification{
+		Kind:    notify.GroupRefunded,
+		Subject: fmt.Sprintf("Your group booking for %s could not be completed", movie.Title),
+		Message: fmt.Sprintf("No ticket could be issued for seats %v, %d was refunded", share.Seats, share.Amount),
+		Data:    map[string]any{"group_id": group.ID, "amount": share.Amount},
+	})
+	return share
+}
+
+// unwind refunds paid shares and releases the block
+func (usecase *useCase) unwind(group entities.GroupBooking, status string, now time.Time) (entities.GroupBooking, error) {
+	shares := make([]entities.GroupShare, len(group.Shares))
+	copy(shares, group.Shares)
+	for i, share := range shares {
+		if !share.Paid {
+			continue
+		}
+		member, err := usecase.booking.GetUser(share.Username)
+		if err == nil {
+			err = usecase.booking.TopUp(&member, share.Amount)
+		}
+		if err != nil {
+			return entities.GroupBooking{}, err
+		}
+		shares[i].Paid = false
+		shares[i].Paid_at = time.Time{}
+	}
+	if group.Status == entities.GroupApproved {
+		if movie, err := usecase.booking.GetMovie(group.MovieID); err == nil {
+			// Holds that already lapsed are skipped
+			usecase.booking.ReleaseReserved(blockSeats(movie, group.Seats), &movie, group.Holder())
+		}
+	}
+
+	group.Shares = shares
+	group.Status = status
+	group.Updated_at = now
+	if err := usecase.groupRepo.Update(group); err != nil {
+		return entities.GroupBooking{}, err
+	}
+	return group, nil
+}
+
+func (usecase *useCase) read(id, status string) (entities.GroupBooking, error) {
+	group, err := usecase.groupRepo.Read(id)
+	if err != nil {
+		return entities.GroupBooking{}, ErrGroupNotFound
+	}
+	if group.Status != status {
+		return entities.GroupBooking{}, ErrInvalidStatus
+	}
+	return group, nil
+}
+
+func (usecase *useCase) send(userID, username string, n notify.Notification) {
+	n.UserID = userID
+	n.Username = username
+	if err := usecase.notifier.Notify(n); err != nil {
+		slog.Error("UseCase.Group.Notify.01", "error", err, "kind", n.Kind)
+	}
+}
+
+// shareBreakdown keeps the price lines of the given seats
+func shareBreakdown(b entities.PriceBreakdown, seats []string) entities.PriceBreakdown {
+	wanted := make(map[string]bool, len(seats))
+	for _, seat := range seats {
+		wanted[seat] = true
+	}
+	share := entities.PriceBreakdown{
+		DemandMultiplier: b.DemandMultiplier,
+		QuotedAt:         b.QuotedAt,
+	}
+	for _, item := range b.Items {
+		if wanted[item.Seat] {
+			share.Items = append(share.Items, item)
+			share.Subtotal += item.Price
+		}
+	}
+	share.Total = share.Subtotal
+	return share
+}
+
+// blockSeats finds the labelled seats in the movie. Only their positions are
+// read, their state belongs to the booking side and its lock
+func blockSeats(m entities.Movie, labels []string) []entities.Seat {
+	var seats []entities.Seat
+	for i := range m.Seats {
+		seat := entities.Seat{Row: m.Seats[i].Row, Number: m.Seats[i].Number}
+		for _, label := range labels {
+			if label == fmt.Sprintf("%s%d", seat.Row, seat.Number) {
+				seats = append(seats, seat)
+			}
+		}
+	}
+	return seats
+}
+
+func labels(seats []entities.Seat) []string {
+	out := make([]string, 0, len(seats))
+	for _, seat := range seats {
+		out = append(out, fmt.Sprintf("%s%d", seat.Row, seat.Number))
+	}
+	return out
+}
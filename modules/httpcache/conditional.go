@@ -0,0 +1,145 @@
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"movie-app-go/modules/compress"
+
+	"github.com/gin-gonic/gin"
+)
+
+const validatorsKey = "HTTPCacheValidators"
+
+// validators are what a handler tells Conditional about its response
+type validators struct {
+	tag      string
+	modified time.Time
+}
+
+// SetValidators describes the response being built. The ETag is derived
+// from tag, which has to change whenever the body does, and modified is
+// the Last-Modified time, the zero time leaves the header out
+func SetValidators(c *gin.Context, tag string, modified time.Time) {
+	c.Set(validatorsKey, validators{tag: tag, modified: modified})
+}
+
+// Conditional adds a strong ETag and Last-Modified, as given to
+// SetValidators, to successful GET responses and answers 304 Not Modified
+// when the client's copy is still current. Responses without validators
+// pass through untouched
+func Conditional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		// Put the real writer back even on panic, recovery has to reach
+		// the client
+		defer func() { c.Writer = w.ResponseWriter }()
+		c.Next()
+
+		value, ok := c.Get(validatorsKey)
+		if w.status != http.StatusOK || !ok {
+			w.ResponseWriter.WriteHeader(w.status)
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+		v := value.(validators)
+
+		sum := sha256.Sum256([]byte(v.tag))
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		header := w.ResponseWriter.Header()
+		header.Set("ETag", etag)
+		modified := lastModified(v.modified, time.Now())
+		if !modified.IsZero() {
+			header.Set("Last-Modified", modified.Format(http.TimeFormat))
+		}
+		// The catalog changes with every booking, have clients revalidate
+		header.Set("Cache-Control", "no-cache")
+
+		if notModified(c.Request, etag, modified) {
+			header.Del("Content-Type")
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			w.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		w.ResponseWriter.WriteHeader(http.StatusOK)
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// lastModified rounds the modification time down to an HTTP date. HTTP
+// dates have whole seconds, another change within the second being served
+// would go unnoticed, so a change that recent gives no Last-Modified
+func lastModified(modified, now time.Time) time.Time {
+	modified = modified.UTC().Truncate(time.Second)
+	if !modified.Before(now.UTC().Truncate(time.Second)) {
+		return time.Time{}
+	}
+	return modified
+}
+
+// notModified follows RFC 9110, If-None-Match decides when present and
+// If-Modified-Since is only looked at without it
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" {
+				return true
+			}
+			// Weak comparison, a W/ prefix does not matter here
+			candidate = compress.StripETag(strings.TrimPrefix(candidate, "W/"))
+			if candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// bufferedWriter holds the response back until its validators are known
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
@@ -0,0 +1,139 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hourAgo := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	tests := []struct {
+		name string
+		// tag and modified are what the handler reports, none sets no
+		// validators at all
+		tag      string
+		modified time.Time
+		none     bool
+		// request sets the conditional headers, given the ETag of the tag
+		request          func(r *http.Request, etag string)
+		wantStatus       int
+		wantLastModified bool
+	}{
+		{
+			name:             "first request",
+			tag:              "v1",
+			modified:         hourAgo,
+			wantStatus:       http.StatusOK,
+			wantLastModified: true,
+		},
+		{
+			name:     "matching ETag",
+			tag:      "v1",
+			modified: hourAgo,
+			request: func(r *http.Request, etag string) {
+				r.Header.Set("If-None-Match", etag)
+			},
+			wantStatus:       http.StatusNotModified,
+			wantLastModified: true,
+		},
+		{
+			name:     "changed version",
+			tag:      "v2",
+			modified: hourAgo,
+			request: func(r *http.Request, _ string) {
+				r.Header.Set("If-None-Match", etagOf("v1"))
+			},
+			wantStatus:       http.StatusOK,
+			wantLastModified: true,
+		},
+		{
+			name:     "not modified since",
+			tag:      "v1",
+			modified: hourAgo,
+			request: func(r *http.Request, _ string) {
+				r.Header.Set("If-Modified-Since", hourAgo.Format(http.TimeFormat))
+			},
+			wantStatus:       http.StatusNotModified,
+			wantLastModified: true,
+		},
+		{
+			name:     "modified since",
+			tag:      "v1",
+			modified: hourAgo,
+			request: func(r *http.Request, _ string) {
+				r.Header.Set("If-Modified-Since", hourAgo.Add(-time.Minute).Format(http.TimeFormat))
+			},
+			wantStatus:       http.StatusOK,
+			wantLastModified: true,
+		},
+		{
+			name:     "changed within the current second",
+			tag:      "v1",
+			modified: time.Now(),
+			request: func(r *http.Request, _ string) {
+				r.Header.Set("If-Modified-Since", time.Now().Add(time.Minute).Format(http.TimeFormat))
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "no modification time",
+			tag:  "v1",
+			request: func(r *http.Request, _ string) {
+				r.Header.Set("If-Modified-Since", time.Now().Format(http.TimeFormat))
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "no validators",
+			none: true,
+			request: func(r *http.Request, _ string) {
+				r.Header.Set("If-None-Match", "*")
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/movies", Conditional(), func(c *gin.Context) {
+				if !tt.none {
+					SetValidators(c, tt.tag, tt.modified)
+				}
+				c.String(http.StatusOK, "movies")
+			})
+			r := httptest.NewRequest(http.MethodGet, "/movies", nil)
+			if tt.request != nil {
+				tt.request(r, etagOf(tt.tag))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); tt.none != (got == "") {
+				t.Errorf("ETag = %q", got)
+			}
+			if got := w.Header().Get("Last-Modified"); tt.wantLastModified != (got != "") {
+				t.Errorf("Last-Modified = %q", got)
+			}
+		})
+	}
+}
+
+// etagOf is the ETag Conditional sends for a tag
+func etagOf(tag string) string {
+	router := gin.New()
+	router.GET("/", Conditional(), func(c *gin.Context) {
+		SetValidators(c, tag, time.Time{})
+		c.String(http.StatusOK, "")
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w.Header().Get("ETag")
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	requestIDKey    = "RequestID"
+	usernameKey     = "LogUsername"
+)
+
+// Setup installs a JSON slog logger as the process default
+func Setup(w io.Writer, level slog.Level) *slog.Logger {
+	l := slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(l)
+	return l
+}
+
+// RequestID returns the request ID assigned by Middleware
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// SetUsername records the authenticated username for later log lines
+func SetUsername(c *gin.Context, username string) {
+	c.Set(usernameKey, username)
+}
+
+// FromContext returns the default logger annotated with the request ID and,
+// once the auth middleware has run, the authenticated username
+func FromContext(c *gin.Context) *slog.Logger {
+	l := slog.Default().With("request_id", RequestID(c))
+	if username := c.GetString(usernameKey); username != "" {
+		l = l.With("username", username)
+	}
+	return l
+}
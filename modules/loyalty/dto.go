@@ -0,0 +1,8 @@
+package loyalty
+
+type Response struct {
+	Code      int    `json:"code" binding:"required"`
+	Message   string `json:"message" binding:"required"`
+	Data      any    `json:"data" binding:"required"`
+	RequestID string `json:"request_id,omitempty"`
+}
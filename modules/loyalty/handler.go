@@ -0,0 +1,61 @@
+package loyalty
+
+import (
+	"net/http"
+
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+	"movie-app-go/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	loyaltyUseCase UseCaseInterface
+	userRepo       repositories.UserRepositoryInterface
+}
+
+type HandlerInterface interface {
+	GetLoyalty(c *gin.Context)
+}
+
+func NewHandler(loyaltyUseCase UseCaseInterface, userRepo repositories.UserRepositoryInterface) HandlerInterface {
+	return &handler{
+		loyaltyUseCase: loyaltyUseCase,
+		userRepo:       userRepo,
+	}
+}
+
+func (h handler) GetLoyalty(c *gin.Context) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.userRepo.GetByUsername(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetLoyalty.01", "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	summary, err := h.loyaltyUseCase.GetSummary(user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetLoyalty.02", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    summary,
+	})
+}
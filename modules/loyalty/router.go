@@ -0,0 +1,10 @@
+package loyalty
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+	LoyaltyRouter := r.Group("/user")
+	LoyaltyRouter.GET("/loyalty", middleware, h.GetLoyalty)
+}
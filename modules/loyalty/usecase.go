@@ -0,0 +1,242 @@
+package loyalty
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+const tierWindow = 365 * 24 * time.Hour
+
+var (
+	ErrNegativePoints     = errors.New("NEGATIVE_VALUE")
+	ErrInsufficientPoints = errors.New("POINTS_INSUFFICIENT")
+)
+
+// Program holds the earning and redemption rules
+type Program struct {
+	// EarnRate is the number of points earned per unit of currency spent
+	EarnRate float64
+	// PointValue is what one point is worth at checkout
+	PointValue int
+	// MaxRedeemShare caps the share of an order payable with points
+	MaxRedeemShare float64
+	// SilverSpend and GoldSpend are the yearly spend needed for each tier
+	SilverSpend int
+	GoldSpend   int
+}
+
+var tierMultipliers = map[string]float64{
+	entities.TierBronze: 1,
+	entities.TierSilver: 1.25,
+	entities.TierGold:   1.5,
+}
+
+type Summary struct {
+	Balance     int                     `json:"balance"`
+	Tier        string                  `json:"tier"`
+	YearlySpend int                     `json:"yearly_spend"`
+	NextTier    string                  `json:"next_tier,omitempty"`
+	SpendToNext int                     `json:"spend_to_next,omitempty"`
+	PointValue  int                     `json:"point_value"`
+	Ledger      []entities.LoyaltyEntry `json:"ledger"`
+}
+
+type useCase struct {
+	// mu keeps balance checks and ledger writes of one checkout together
+	mu          sync.Mutex
+	loyaltyRepo repositories.LoyaltyRepositoryInterface
+	program     Program
+}
+
+type UseCaseInterface interface {
+	GetSummary(u entities.User) (Summary, error)
+	Quote(u entities.User, points int, b *entities.PriceBreakdown) error
+	Redeem(u entities.User, ticketID string, b entities.PriceBreakdown) error
+	Earn(u entities.User, t entities.Ticket) error
+	Reverse(ticketID string) error
+}
+
+func NewUseCase(loyaltyRepo repositories.LoyaltyRepositoryInterface, program Program) UseCaseInterface {
+	return &useCase{
+		loyaltyRepo: loyaltyRepo,
+		program:     program,
+	}
+}
+
+func (usecase *useCase) GetSummary(u entities.User) (Summary, error) {
+	entries, err := usecase.loyaltyRepo.ReadByUser(u.ID)
+	if err != nil {
+		return Summary{}, err
+	}
+	balance, spend := totals(entries, time.Now())
+	summary := Summary{
+		Balance:     balance,
+		Tier:        usecase.tier(spend),
+		YearlySpend: spend,
+		PointValue:  usecase.program.PointValue,
+		Ledger:      entries,
+	}
+	switch summary.Tier {
+	case entities.TierBronze:
+		summary.NextTier = entities.TierSilver
+		summary.SpendToNext = usecase.program.SilverSpend - spend
+	case entities.TierSilver:
+		summary.NextTier = entities.TierGold
+		summary.SpendToNext = usecase.program.GoldSpend - spend
+	}
+	if summary.Ledger == nil {
+		summary.Ledger = []entities.LoyaltyEntry{}
+	}
+	return summary, nil
+}
+
+// Quote adds a points discount to the breakdown. It uses no more points than
+// the capped discount needs, the points are only spent by Redeem
+func (usecase *useCase) Quote(u entities.User, points int, b *entities.PriceBreakdown) error {
+	if points < 0 {
+		return ErrNegativePoints
+	}
+	if points == 0 || usecase.program.PointValue <= 0 {
+		return nil
+	}
+	entries, err := usecase.loyaltyRepo.ReadByUser(u.ID)
+	if err != nil {
+		return err
+	}
+	if balance, _ := totals(entries, time.Now()); balance < points {
+		return ErrInsufficientPoints
+	}
+
+	limit := int(float64(b.Total) * usecase.program.MaxRedeemShare)
+	discount := points * usecase.program.PointValue
+	if discount > limit {
+		points = limit / usecase.program.PointValue
+		discount = points * usecase.program.PointValue
+	}
+	if points == 0 {
+		return nil
+	}
+	b.Adjust(entities.PriceAdjustment{
+		Kind:        entities.AdjustmentPoints,
+		Description: fmt.Sprintf("%d loyalty points", points),
+		Amount:      -discount,
+		Points:      points,
+	})
+	return nil
+}
+
+// Redeem spends the points quoted in the breakdown, checking the balance
+// again so two checkouts cannot spend the same points
+func (usecase *useCase) Redeem(u entities.User, ticketID string, b entities.PriceBreakdown) error {
+	points := 0
+	for _, adjustment := range b.Adjustments {
+		if adjustment.Kind == entities.AdjustmentPoints {
+			points += adjustment.Points
+		}
+	}
+	if points == 0 {
+		return nil
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	entries, err := usecase.loyaltyRepo.ReadByUser(u.ID)
+	if err != nil {
+		return err
+	}
+	if balance, _ := totals(entries, time.Now()); balance < points {
+		return ErrInsufficientPoints
+	}
+	return usecase.record(u.ID, ticketID, entities.LoyaltyRedeem, -points, 0)
+}
+
+// Earn credits points for the cash paid on a ticket, boosted by the user's
+// tier before the purchase
+func (usecase *useCase) Earn(u entities.User, t entities.Ticket) error {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	entries, err := usecase.loyaltyRepo.ReadByUser(u.ID)
+	if err != nil {
+		return err
+	}
+	_, spend := totals(entries, time.Now())
+	multiplier := tierMultipliers[usecase.tier(spend)]
+	points := int(math.Floor(float64(t.Cost) * usecase.program.EarnRate * multiplier))
+
+	return usecase.record(u.ID, t.ID, entities.LoyaltyEarn, points, t.Cost)
+}
+
+// Reverse undoes every ledger entry of a cancelled ticket with an opposite
+// entry, which may leave the balance negative if earned points were spent
+func (usecase *useCase) Reverse(ticketID string) error {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	entries, err := usecase.loyaltyRepo.ReadByTicket(ticketID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		kind := ""
+		switch entry.Kind {
+		case entities.LoyaltyEarn:
+			kind = entities.LoyaltyReverseEarn
+		case entities.LoyaltyRedeem:
+			kind = entities.LoyaltyReverseRedeem
+		default:
+			continue
+		}
+		if err := usecase.record(entry.UserID, ticketID, kind, -entry.Points, -entry.Spend); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (usecase *useCase) record(userID, ticketID, kind string, points, spend int) error {
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	return usecase.loyaltyRepo.Create(entities.LoyaltyEntry{
+		ID:         UUID.String(),
+		UserID:     userID,
+		TicketID:   ticketID,
+		Kind:       kind,
+		Points:     points,
+		Spend:      spend,
+		Created_at: time.Now(),
+	})
+}
+
+func (usecase *useCase) tier(spend int) string {
+	switch {
+	case spend >= usecase.program.GoldSpend:
+		return entities.TierGold
+	case spend >= usecase.program.SilverSpend:
+		return entities.TierSilver
+	}
+	return entities.TierBronze
+}
+
+// totals returns the points balance and the spend of the last year
+func totals(entries []entities.LoyaltyEntry, at time.Time) (int, int) {
+	balance, spend := 0, 0
+	for _, entry := range entries {
+		balance += entry.Points
+		if at.Sub(entry.Created_at) <= tierWindow {
+			spend += entry.Spend
+		}
+	}
+	return balance, spend
+}
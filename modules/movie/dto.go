@@ -5,15 +5,47 @@ type RequestInterface interface {
 }
 
 type Request struct {
-	Page    string `form:"page" validate:"blacklist"`
-	Limit   int    `form:"limit"`
-	Search  string `form:"search"`
-	OrderBy string `form:"orderBy"`
-	SortBy  string `form:"sortBy"`
+	Page           string `form:"page" validate:"blacklist"`
+	Limit          int    `form:"limit"`
+	Search         string `form:"search"`
+	OrderBy        string `form:"orderBy" binding:"omitempty,oneof=asc desc"`
+	SortBy         string `form:"sortBy" binding:"omitempty,oneof=rating"`
+	Genre          string `form:"genre" validate:"blacklist"`
+	Language       string `form:"language" validate:"blacklist"`
+	Max_age_rating *int   `form:"max_age_rating" binding:"omitempty,min=0"`
+	Released_from  string `form:"released_from" binding:"omitempty,datetime=2006-01-02"`
+	Released_to    string `form:"released_to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// SearchRequest is shared by search and suggest
+type SearchRequest struct {
+	Query string `form:"q" binding:"required,max=200"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// UpdateRequest edits movie metadata, omitted fields are left unchanged
+type UpdateRequest struct {
+	Genres      *[]string `json:"genres" binding:"omitempty,max=10,dive,max=40"`
+	Runtime     *int      `json:"runtime" binding:"omitempty,min=1,max=600"`
+	Language    *string   `json:"language" binding:"omitempty,max=40"`
+	Director    *string   `json:"director" binding:"omitempty,max=100"`
+	Cast        *[]string `json:"cast" binding:"omitempty,max=50,dive,max=100"`
+	Trailer_url *string   `json:"trailer_url" binding:"omitempty,url"`
+}
+
+type SeatMapRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json text bitset"`
+}
+
+type BestSeatsRequest struct {
+	PartySize      int    `form:"party_size" binding:"required,min=1"`
+	PreferredRow   string `form:"preferred_row" validate:"blacklist"`
+	ContiguousOnly bool   `form:"contiguous_only"`
 }
 
 type Response struct {
-	Code    int    `json:"code" binding:"required"`
-	Message string `json:"message" binding:"required"`
-	Data    any    `json:"data" binding:"required"`
+	Code      int    `json:"code" binding:"required"`
+	Message   string `json:"message" binding:"required"`
+	Data      any    `json:"data" binding:"required"`
+	RequestID string `json:"request_id,omitempty"`
 }
@@ -2,9 +2,16 @@ package movie
 
 import (
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/httpcache"
+	"movie-app-go/modules/logger"
+	"movie-app-go/modules/seating"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -17,9 +24,22 @@ type handler struct {
 
 type HandlerInterface interface {
 	GetMovies(c *gin.Context)
+	GetNowShowing(c *gin.Context)
+	GetComingSoon(c *gin.Context)
 	GetMovieDetails(c *gin.Context)
+	GetSeatMap(c *gin.Context)
+	GetBestSeats(c *gin.Context)
+	GetQuote(c *gin.Context)
+	UpdateMovie(c *gin.Context)
+	SearchMovies(c *gin.Context)
+	SuggestMovies(c *gin.Context)
 }
 
+const (
+	defaultSearchLimit  = 20
+	defaultSuggestLimit = 8
+)
+
 var (
 	ErrMovieMissing   = errors.New("movie does not exist")
 	ErrInternalServer = errors.New("internal server error")
@@ -40,38 +60,78 @@ func (h handler) GetMovies(c *gin.Context) {
 	var req Request
 
 	if err := c.ShouldBindQuery(&req); err != nil {
-		log.Println("Handler.GetMovies.01", err.Error())
+		logger.FromContext(c).Error("Handler.GetMovies.01", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "FAILED_BIND_QUERY",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	if err := h.validate.Struct(req); err != nil {
-		log.Println("Handler.GetMovies.02", err.Error())
+		logger.FromContext(c).Error("Handler.GetMovies.02", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "VALIDATION_ERROR",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "VALIDATION_ERROR",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
-	movies, err := h.movieUseCase.GetAll()
+	opts := ListOptions{
+		Search:       req.Search,
+		Genre:        req.Genre,
+		Language:     req.Language,
+		MaxAgeRating: req.Max_age_rating,
+		SortBy:       req.SortBy,
+		OrderBy:      req.OrderBy,
+	}
+	if from, err := entities.ParseDate(req.Released_from); err == nil {
+		opts.ReleasedFrom = from.Time
+	}
+	if to, err := entities.ParseDate(req.Released_to); err == nil {
+		opts.ReleasedTo = to.Time
+	}
+	movies, err := h.movieUseCase.GetAll(opts)
 	if err != nil {
-		log.Println("Handler.GetMovies.02", err.Error())
+		logger.FromContext(c).Error("Handler.GetMovies.02", "error", err)
 
 		c.JSON(http.StatusInternalServerError, Response{
-			Code:    http.StatusInternalServerError,
-			Message: "FAILED_USECASE",
-			Data:    err.Error(),
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
+	h.setValidators(c, movies, true)
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    movies,
+	})
+}
+
+func (h handler) GetNowShowing(c *gin.Context) {
+	movies := h.movieUseCase.NowShowing(time.Now())
+	// Time alone moves movies in and out, no Last-Modified tells when
+	h.setValidators(c, movies, false)
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    movies,
+	})
+}
+
+func (h handler) GetComingSoon(c *gin.Context) {
+	movies := h.movieUseCase.ComingSoon(time.Now())
+	// Time alone moves movies in and out, no Last-Modified tells when
+	h.setValidators(c, movies, false)
 	c.JSON(http.StatusOK, Response{
 		Code:    http.StatusOK,
 		Message: "SUCCESS",
@@ -82,30 +142,321 @@ func (h handler) GetMovies(c *gin.Context) {
 func (h handler) GetMovieDetails(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Println("Handler.GetMovieDetails.01", err.Error())
+		logger.FromContext(c).Error("Handler.GetMovieDetails.01", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "FAILED_CONVERT_ID",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_CONVERT_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	movies, err := h.movieUseCase.GetById(id)
 	if err != nil {
-		log.Println("Handler.GetMovieDetails.02", err.Error())
+		logger.FromContext(c).Error("Handler.GetMovieDetails.02", "error", err)
 
 		c.JSON(http.StatusInternalServerError, Response{
-			Code:    http.StatusInternalServerError,
-			Message: "FAILED_USECASE",
-			Data:    err.Error(),
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
+	h.setValidators(c, []entities.Movie{movies}, true)
 	c.JSON(http.StatusOK, Response{
 		Code:    http.StatusOK,
 		Message: "SUCCESS",
 		Data:    movies,
 	})
 }
+
+func (h handler) GetSeatMap(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetSeatMap.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_CONVERT_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	var req SeatMapRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetSeatMap.02", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	seatMap, title, err := h.movieUseCase.GetSeatMap(id)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetSeatMap.03", "error", err)
+
+		c.JSON(http.StatusNotFound, Response{
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	switch req.Format {
+	case "text":
+		c.String(http.StatusOK, seatMap.RenderText(title))
+	case "bitset":
+		c.JSON(http.StatusOK, Response{
+			Code:    http.StatusOK,
+			Message: "SUCCESS",
+			Data:    seatMap.Bitset(),
+		})
+	default:
+		c.JSON(http.StatusOK, Response{
+			Code:    http.StatusOK,
+			Message: "SUCCESS",
+			Data:    seatMap,
+		})
+	}
+}
+
+func (h handler) GetBestSeats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetBestSeats.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_CONVERT_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	var req BestSeatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetBestSeats.02", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		logger.FromContext(c).Error("Handler.GetBestSeats.03", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "VALIDATION_ERROR",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	suggestion, err := h.movieUseCase.GetBestSeats(id, seating.Options{
+		PartySize:      req.PartySize,
+		PreferredRow:   req.PreferredRow,
+		ContiguousOnly: req.ContiguousOnly,
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetBestSeats.04", "error", err)
+
+		status := http.StatusNotFound
+		if errors.Is(err, seating.ErrInvalidRow) || errors.Is(err, seating.ErrPartyTooLarge) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "NO_SEATS_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    suggestion,
+	})
+}
+
+func (h handler) GetQuote(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetQuote.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_CONVERT_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	quote, err := h.movieUseCase.GetQuote(id)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetQuote.02", "error", err)
+
+		c.JSON(http.StatusNotFound, Response{
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    quote,
+	})
+}
+
+func (h handler) UpdateMovie(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.UpdateMovie.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_CONVERT_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.UpdateMovie.02", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	movie, err := h.movieUseCase.UpdateDetails(id, Details{
+		Genres:      req.Genres,
+		Runtime:     req.Runtime,
+		Language:    req.Language,
+		Director:    req.Director,
+		Cast:        req.Cast,
+		Trailer_url: req.Trailer_url,
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Handler.UpdateMovie.03", "error", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrMovieNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_UPDATE_MOVIE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "MOVIE_UPDATED",
+		Data:    movie,
+	})
+}
+
+func (h handler) SearchMovies(c *gin.Context) {
+	var req SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.SearchMovies.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSearchLimit
+	}
+	results, err := h.movieUseCase.Search(req.Query, req.Limit)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.SearchMovies.02", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    results,
+	})
+}
+
+func (h handler) SuggestMovies(c *gin.Context) {
+	var req SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.SuggestMovies.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSuggestLimit
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    h.movieUseCase.Suggest(req.Query, req.Limit),
+	})
+}
+
+// setValidators derives the validators of a response listing movies from
+// the catalog version and the movies listed. Last-Modified is the latest of
+// the catalog's last change and the movies' Updated_at
+func (h handler) setValidators(c *gin.Context, movies []entities.Movie, lastModified bool) {
+	version, modified := h.movieUseCase.Version()
+	var tag strings.Builder
+	tag.WriteString(version)
+	for _, movie := range movies {
+		fmt.Fprintf(&tag, ":%d@%d", movie.ID, movie.Updated_at.UnixNano())
+		if movie.Updated_at.After(modified) {
+			modified = movie.Updated_at
+		}
+	}
+	if !lastModified {
+		modified = time.Time{}
+	}
+	httpcache.SetValidators(c, tag.String(), modified)
+}
@@ -1,15 +1,30 @@
 package movie
 
 import (
+	"movie-app-go/modules/httpcache"
+
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
-func SetupRouter(r *gin.Engine, h HandlerInterface) {
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc, adminOnly gin.HandlerFunc) {
 	validate := validator.New()
 	validate.RegisterValidation("blacklist", BlacklistValidation)
 
+	// The catalog listings answer conditional requests
+	conditional := httpcache.Conditional()
+
 	MovieRouter := r.Group("/")
-	MovieRouter.GET("movies", h.GetMovies)
-	MovieRouter.GET("movie/details/:id", h.GetMovieDetails)
+	MovieRouter.GET("movies", conditional, h.GetMovies)
+	MovieRouter.GET("movies/now-showing", conditional, h.GetNowShowing)
+	MovieRouter.GET("movies/coming-soon", conditional, h.GetComingSoon)
+	MovieRouter.GET("movies/search", h.SearchMovies)
+	MovieRouter.GET("movies/suggest", h.SuggestMovies)
+	MovieRouter.GET("movie/details/:id", conditional, h.GetMovieDetails)
+	MovieRouter.GET("movie/:id/seatmap", h.GetSeatMap)
+	MovieRouter.GET("movie/:id/best-seats", h.GetBestSeats)
+	MovieRouter.GET("movie/:id/quote", h.GetQuote)
+
+	AdminRouter := r.Group("/admin/movies", middleware, adminOnly)
+	AdminRouter.PATCH("/:id", h.UpdateMovie)
 }
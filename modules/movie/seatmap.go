@@ -0,0 +1,162 @@
+package movie
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"movie-app-go/entities"
+)
+
+type (
+	SeatMapCell struct {
+		Seat      string `json:"seat"`
+		Number    int    `json:"number"`
+		Status    string `json:"status"`
+		Type      string `json:"type"`
+		PriceTier string `json:"price_tier"`
+		Price     int    `json:"price"`
+	}
+	SeatMapRow struct {
+		Row   string        `json:"row"`
+		Seats []SeatMapCell `json:"seats"`
+	}
+	SeatMap struct {
+		MovieID int            `json:"movie_id"`
+		Columns int            `json:"columns"`
+		Rows    []SeatMapRow   `json:"rows"`
+		Summary map[string]int `json:"summary"`
+	}
+	// SeatMapBitset packs availability into one bit per grid position,
+	// row-major and most significant bit first. A set bit means the seat
+	// can be booked, positions without a seat are always zero
+	SeatMapBitset struct {
+		MovieID   int      `json:"movie_id"`
+		Rows      []string `json:"rows"`
+		Columns   int      `json:"columns"`
+		Available string   `json:"available"`
+	}
+)
+
+var seatStatusSymbols = map[string]string{
+	entities.SeatAvailable: ".",
+	entities.SeatBooked:    "X",
+	entities.SeatHeld:      "H",
+	entities.SeatBlocked:   "#",
+}
+
+// BuildSeatMap groups the flat seat list of a movie into rows ordered as they
+// appear in the inventory, seats within a row ordered by number
+func BuildSeatMap(m entities.Movie, at time.Time, price func(entities.Seat) int) SeatMap {
+	seatMap := SeatMap{
+		MovieID: m.ID,
+		Summary: map[string]int{
+			entities.SeatAvailable: 0,
+			entities.SeatBooked:    0,
+			entities.SeatHeld:      0,
+			entities.SeatBlocked:   0,
+		},
+	}
+	rowIndex := make(map[string]int)
+
+	for _, seat := range m.Seats {
+		i, ok := rowIndex[seat.Row]
+		if !ok {
+			i = len(seatMap.Rows)
+			rowIndex[seat.Row] = i
+			seatMap.Rows = append(seatMap.Rows, SeatMapRow{Row: seat.Row})
+		}
+		status := seat.Status(at)
+		seatMap.Rows[i].Seats = append(seatMap.Rows[i].Seats, SeatMapCell{
+			Seat:      fmt.Sprintf("%s%d", seat.Row, seat.Number),
+			Number:    seat.Number,
+			Status:    status,
+			Type:      seat.Type,
+			PriceTier: seat.Type,
+			Price:     price(seat),
+		})
+		seatMap.Summary[status]++
+		if seat.Number > seatMap.Columns {
+			seatMap.Columns = seat.Number
+		}
+	}
+	for _, row := range seatMap.Rows {
+		sort.Slice(row.Seats, func(a, b int) bool {
+			return row.Seats[a].Number < row.Seats[b].Number
+		})
+	}
+
+	return seatMap
+}
+
+// RenderText draws the seat map as a fixed-width grid for kiosk displays
+func (s SeatMap) RenderText(title string) string {
+	var b strings.Builder
+	width := 4 + s.Columns*3
+
+	if title != "" {
+		b.WriteString(title + "\n")
+	}
+	b.WriteString(center("SCREEN", width) + "\n")
+	b.WriteString(strings.Repeat("-", width) + "\n")
+
+	b.WriteString("    ")
+	for n := 1; n <= s.Columns; n++ {
+		fmt.Fprintf(&b, "%3d", n)
+	}
+	b.WriteString("\n")
+
+	for _, row := range s.Rows {
+		cells := make([]string, s.Columns+1)
+		for _, cell := range row.Seats {
+			if cell.Number >= 1 {
+				cells[cell.Number] = seatStatusSymbols[cell.Status]
+			}
+		}
+		fmt.Fprintf(&b, "%-4s", row.Row)
+		for n := 1; n <= s.Columns; n++ {
+			symbol := cells[n]
+			if symbol == "" {
+				symbol = " "
+			}
+			fmt.Fprintf(&b, "%3s", symbol)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n. available  X booked  H held  # blocked\n")
+	return b.String()
+}
+
+// Bitset encodes seat availability for low-bandwidth clients
+func (s SeatMap) Bitset() SeatMapBitset {
+	bits := make([]byte, (len(s.Rows)*s.Columns+7)/8)
+	rows := make([]string, 0, len(s.Rows))
+
+	for r, row := range s.Rows {
+		rows = append(rows, row.Row)
+		for _, cell := range row.Seats {
+			if cell.Status != entities.SeatAvailable || cell.Number < 1 {
+				continue
+			}
+			pos := r*s.Columns + cell.Number - 1
+			bits[pos/8] |= 0x80 >> (pos % 8)
+		}
+	}
+
+	return SeatMapBitset{
+		MovieID:   s.MovieID,
+		Rows:      rows,
+		Columns:   s.Columns,
+		Available: base64.StdEncoding.EncodeToString(bits),
+	}
+}
+
+func center(text string, width int) string {
+	if len(text) >= width {
+		return text
+	}
+	return strings.Repeat(" ", (width-len(text))/2) + text
+}
@@ -1,22 +1,106 @@
 package movie
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"movie-app-go/entities"
+	"movie-app-go/modules/pricing"
+	"movie-app-go/modules/search"
+	"movie-app-go/modules/seating"
 	"movie-app-go/repositories"
 )
 
+// Listing orders
+const (
+	SortByRating = "rating"
+	OrderAsc     = "asc"
+	OrderDesc    = "desc"
+)
+
+var ErrMovieNotFound = errors.New("MOVIE_NOT_FOUND")
+
+// ListOptions filters and orders the movie listing, zero values match
+// every movie. A release date range leaves out movies without a release
+// date
+type ListOptions struct {
+	// Search keeps the movies matching the query, ordered by relevance
+	// unless another order is asked for
+	Search       string
+	Genre        string
+	Language     string
+	MaxAgeRating *int
+	ReleasedFrom time.Time
+	ReleasedTo   time.Time
+	SortBy       string
+	OrderBy      string
+}
+
+// SearchResult is a movie matching a search with its relevance
+type SearchResult struct {
+	entities.Movie
+	Score float64 `json:"score"`
+}
+
+// Details holds the metadata admins can edit, nil fields are left alone
+type Details struct {
+	Genres      *[]string
+	Runtime     *int
+	Language    *string
+	Director    *string
+	Cast        *[]string
+	Trailer_url *string
+}
+
+// Ratings aggregates the reviews shown with movies
+type Ratings interface {
+	Summary(movieID int) entities.RatingSummary
+	Summaries() map[int]entities.RatingSummary
+	Version() (uint64, time.Time)
+}
+
 type useCase struct {
 	movieRepo repositories.MovieRepositoryInterface
+	pricing   pricing.ServiceInterface
+	ratings   Ratings
+	index     search.IndexInterface
+	maxSeats  int
+	// started tells the versions of one run from those of the last, the
+	// counters behind them start over with the data
+	started time.Time
 }
 
 type UseCaseInterface interface {
 	GetById(id int) (entities.Movie, error)
-	GetAll() ([]entities.Movie, error)
+	GetAll(opts ListOptions) ([]entities.Movie, error)
+	NowShowing(now time.Time) []entities.Movie
+	ComingSoon(now time.Time) []entities.Movie
+	UpdateDetails(id int, d Details) (entities.Movie, error)
+	Search(query string, limit int) ([]SearchResult, error)
+	Suggest(query string, limit int) []search.Hit
+	GetSeatMap(id int) (SeatMap, string, error)
+	GetBestSeats(id int, opts seating.Options) (seating.Suggestion, error)
+	GetQuote(id int) (pricing.MovieQuote, error)
+	Version() (string, time.Time)
 }
 
-func NewUseCase(movieRepo repositories.MovieRepositoryInterface) UseCaseInterface {
+// NewUseCase indexes every movie in the repository for search, later
+// changes made through the use case are indexed as they happen
+func NewUseCase(movieRepo repositories.MovieRepositoryInterface, pricing pricing.ServiceInterface, ratings Ratings, index search.IndexInterface, maxSeats int) UseCaseInterface {
+	movies, _ := movieRepo.ReadAll()
+	for _, movie := range movies {
+		index.Put(movie)
+	}
 	return &useCase{
 		movieRepo: movieRepo,
+		pricing:   pricing,
+		ratings:   ratings,
+		index:     index,
+		maxSeats:  maxSeats,
+		started:   time.Now(),
 	}
 }
 
@@ -26,12 +110,239 @@ func (usecase *useCase) GetById(id int) (entities.Movie, error) {
 	if err != nil {
 		return entities.Movie{}, err
 	}
+	summary := usecase.ratings.Summary(movie.ID)
+	movie.Rating = &summary
+
+	return movie, nil
+}
+
+// GetAll lists movies with their ratings. Sorted by rating the best rated
+// come first unless orderBy is asc, ties go to the movie with more reviews
+func (usecase *useCase) GetAll(opts ListOptions) ([]entities.Movie, error) {
+	stored, _ := usecase.movieRepo.ReadAll()
+	summaries := usecase.ratings.Summaries()
+
+	if opts.Search != "" {
+		hits := usecase.index.Search(opts.Search, search.Options{Fuzzy: true})
+		stored = usecase.moviesFor(hits)
+	}
+	movies := make([]entities.Movie, 0, len(stored))
+	for _, movie := range stored {
+		if !opts.match(movie) {
+			continue
+		}
+		summary := summaries[movie.ID]
+		movie.Rating = &summary
+		movies = append(movies, movie)
+	}
+	if opts.SortBy == SortByRating {
+		orderBy := opts.OrderBy
+		sort.SliceStable(movies, func(a, b int) bool {
+			ra, rb := movies[a].Rating, movies[b].Rating
+			if ra.Average != rb.Average {
+				if orderBy == OrderAsc {
+					return ra.Average < rb.Average
+				}
+				return ra.Average > rb.Average
+			}
+			return ra.Count > rb.Count
+		})
+	}
+
+	return movies, nil
+}
+
+// NowShowing lists released movies that still have a showtime ahead,
+// latest release first
+func (usecase *useCase) NowShowing(now time.Time) []entities.Movie {
+	movies := usecase.filter(func(m entities.Movie) bool {
+		return m.Released(now) && now.Before(m.Showtime)
+	})
+	sort.SliceStable(movies, func(a, b int) bool {
+		return movies[a].Release_date.After(movies[b].Release_date.Time)
+	})
+	return movies
+}
 
+// ComingSoon lists movies that are not released yet, soonest first
+func (usecase *useCase) ComingSoon(now time.Time) []entities.Movie {
+	movies := usecase.filter(func(m entities.Movie) bool {
+		return !m.Released(now)
+	})
+	sort.SliceStable(movies, func(a, b int) bool {
+		return movies[a].Release_date.Before(movies[b].Release_date.Time)
+	})
+	return movies
+}
+
+// UpdateDetails changes a movie's metadata, seats and pricing are untouched
+func (usecase *useCase) UpdateDetails(id int, d Details) (entities.Movie, error) {
+	movie, err := usecase.movieRepo.Read(id)
+	if err != nil {
+		return entities.Movie{}, ErrMovieNotFound
+	}
+	if d.Genres != nil {
+		movie.Genres = cleanList(*d.Genres)
+	}
+	if d.Runtime != nil {
+		movie.Runtime = *d.Runtime
+	}
+	if d.Language != nil {
+		movie.Language = strings.TrimSpace(*d.Language)
+	}
+	if d.Director != nil {
+		movie.Director = strings.TrimSpace(*d.Director)
+	}
+	if d.Cast != nil {
+		movie.Cast = cleanList(*d.Cast)
+	}
+	if d.Trailer_url != nil {
+		movie.Trailer_url = *d.Trailer_url
+	}
+	movie.Updated_at = time.Now()
+	if err := usecase.movieRepo.Update(movie); err != nil {
+		return entities.Movie{}, err
+	}
+	usecase.index.Put(movie)
+	summary := usecase.ratings.Summary(movie.ID)
+	movie.Rating = &summary
 	return movie, nil
 }
 
-func (usecase *useCase) GetAll() ([]entities.Movie, error) {
-	admins, _ := usecase.movieRepo.ReadAll()
+// Search finds movies by title, description and cast, tolerating typos
+func (usecase *useCase) Search(query string, limit int) ([]SearchResult, error) {
+	hits := usecase.index.Search(query, search.Options{Fuzzy: true, Limit: limit})
+	summaries := usecase.ratings.Summaries()
+
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		movie, err := usecase.movieRepo.Read(hit.MovieID)
+		if err != nil {
+			continue
+		}
+		summary := summaries[movie.ID]
+		movie.Rating = &summary
+		results = append(results, SearchResult{Movie: movie, Score: hit.Score})
+	}
+	return results, nil
+}
+
+// Suggest completes a query as it is typed, the last word matching the
+// start of indexed words
+func (usecase *useCase) Suggest(query string, limit int) []search.Hit {
+	return usecase.index.Search(query, search.Options{Prefix: true, Fuzzy: true, Limit: limit})
+}
+
+func (usecase *useCase) GetSeatMap(id int) (SeatMap, string, error) {
+	movie, err := usecase.movieRepo.Read(id)
+	if err != nil {
+		return SeatMap{}, "", err
+	}
+
+	return BuildSeatMap(movie, time.Now(), func(s entities.Seat) int {
+		price, _ := usecase.pricing.SeatPrice(movie, s)
+		return price
+	}), movie.Title, nil
+}
+
+func (usecase *useCase) GetBestSeats(id int, opts seating.Options) (seating.Suggestion, error) {
+	movie, err := usecase.movieRepo.Read(id)
+	if err != nil {
+		return seating.Suggestion{}, err
+	}
+	now := time.Now()
+	opts.MaxPartySize = usecase.maxSeats
+
+	return seating.Suggest(movie.Seats, opts, func(s entities.Seat) bool {
+		return s.Status(now) == entities.SeatAvailable
+	})
+}
+
+func (usecase *useCase) GetQuote(id int) (pricing.MovieQuote, error) {
+	movie, err := usecase.movieRepo.Read(id)
+	if err != nil {
+		return pricing.MovieQuote{}, err
+	}
+
+	return usecase.pricing.CurrentQuote(movie), nil
+}
+
+// Version identifies the state of the catalog, it changes with every
+// write to the movies, their screenings and seats, or their reviews. The
+// time is when the last of those writes happened, zero before any
+func (usecase *useCase) Version() (string, time.Time) {
+	movies, moviesModified := usecase.movieRepo.Version()
+	reviews, reviewsModified := usecase.ratings.Version()
+	modified := moviesModified
+	if reviewsModified.After(modified) {
+		modified = reviewsModified
+	}
+	return fmt.Sprintf("%d.%d.%d", usecase.started.UnixNano(), movies, reviews), modified
+}
+
+// filter returns the movies keep accepts with their ratings
+func (usecase *useCase) filter(keep func(entities.Movie) bool) []entities.Movie {
+	stored, _ := usecase.movieRepo.ReadAll()
+	summaries := usecase.ratings.Summaries()
+
+	movies := make([]entities.Movie, 0, len(stored))
+	for _, movie := range stored {
+		if !keep(movie) {
+			continue
+		}
+		summary := summaries[movie.ID]
+		movie.Rating = &summary
+		movies = append(movies, movie)
+	}
+	return movies
+}
+
+// moviesFor looks up the movies of search hits, keeping their order
+func (usecase *useCase) moviesFor(hits []search.Hit) []entities.Movie {
+	movies := make([]entities.Movie, 0, len(hits))
+	for _, hit := range hits {
+		if movie, err := usecase.movieRepo.Read(hit.MovieID); err == nil {
+			movies = append(movies, movie)
+		}
+	}
+	return movies
+}
+
+func (opts ListOptions) match(m entities.Movie) bool {
+	if opts.Genre != "" && !m.HasGenre(opts.Genre) {
+		return false
+	}
+	if opts.Language != "" && !strings.EqualFold(m.Language, opts.Language) {
+		return false
+	}
+	if opts.MaxAgeRating != nil && m.Age_rating > *opts.MaxAgeRating {
+		return false
+	}
+	if opts.ReleasedFrom.IsZero() && opts.ReleasedTo.IsZero() {
+		return true
+	}
+	released := m.Release_date
+	if released.IsZero() {
+		return false
+	}
+	if !opts.ReleasedFrom.IsZero() && released.Before(opts.ReleasedFrom) {
+		return false
+	}
+	return opts.ReleasedTo.IsZero() || !released.After(opts.ReleasedTo)
+}
 
-	return admins, nil
+// cleanList trims entries and drops blank and repeated ones
+func cleanList(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	cleaned := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, value)
+	}
+	return cleaned
 }
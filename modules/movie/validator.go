@@ -1,7 +1,7 @@
 package movie
 
 import (
-	"log"
+	"log/slog"
 	"regexp"
 
 	"github.com/go-playground/validator/v10"
@@ -10,7 +10,7 @@ import (
 func BlacklistValidation(fl validator.FieldLevel) bool {
 	field := fl.Field().String()
 
-	log.Println(field)
+	slog.Debug("BlacklistValidation", "field", field)
 	if field == "" {
 		return true
 	}
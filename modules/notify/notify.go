@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"log/slog"
+)
+
+// Notification kinds
+const (
+	WaitlistOffer     = "waitlist.offer"
+	WaitlistExpired   = "waitlist.expired"
+	GroupApproved     = "group.approved"
+	GroupRejected     = "group.rejected"
+	GroupInvited      = "group.invited"
+	GroupConfirmed    = "group.confirmed"
+	GroupExpired      = "group.expired"
+	GroupRefunded     = "group.refunded"
+	WatchlistShowtime = "watchlist.showtime"
+	WatchlistReleased = "watchlist.released"
+)
+
+type Notification struct {
+	UserID   string
+	Username string
+	Kind     string
+	Subject  string
+	Message  string
+	Data     map[string]any
+}
+
+// Notifier delivers notifications to users. Implementations for email, push
+// or SMS plug in here, a failed delivery is reported but never undoes the
+// action that caused it
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+type logNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier writes notifications to the log, for development and for
+// deployments without a delivery channel yet
+func NewLogNotifier(logger *slog.Logger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) Notify(notification Notification) error {
+	n.logger.Info("notification",
+		"kind", notification.Kind,
+		"user_id", notification.UserID,
+		"username", notification.Username,
+		"subject", notification.Subject,
+		"message", notification.Message,
+		"data", notification.Data,
+	)
+	return nil
+}
@@ -0,0 +1,12 @@
+package poster
+
+type Request struct {
+	Width int `form:"w" binding:"omitempty,min=1,max=4000"`
+}
+
+type Response struct {
+	Code      int    `json:"code" binding:"required"`
+	Message   string `json:"message" binding:"required"`
+	Data      any    `json:"data" binding:"required"`
+	RequestID string `json:"request_id,omitempty"`
+}
@@ -0,0 +1,83 @@
+package poster
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Posters only change with the catalog, placeholders are kept briefly so
+// the real poster shows up once its host is reachable again
+const (
+	posterCacheControl      = "public, max-age=2592000"
+	placeholderCacheControl = "public, max-age=300"
+)
+
+type handler struct {
+	posterUseCase UseCaseInterface
+}
+
+type HandlerInterface interface {
+	GetPoster(c *gin.Context)
+}
+
+func NewHandler(posterUseCase UseCaseInterface) HandlerInterface {
+	return &handler{
+		posterUseCase: posterUseCase,
+	}
+}
+
+func (h handler) GetPoster(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetPoster.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_CONVERT_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	var req Request
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetPoster.02", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	img, err := h.posterUseCase.Get(id, req.Width)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetPoster.03", "error", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrMovieNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_POSTER",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	if img.Placeholder {
+		c.Header("Cache-Control", placeholderCacheControl)
+	} else {
+		c.Header("Cache-Control", posterCacheControl)
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
@@ -0,0 +1,45 @@
+package poster
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	"golang.org/x/image/draw"
+)
+
+// placeholderWidth sizes the placeholder when the full size was asked for
+const placeholderWidth = 300
+
+var (
+	placeholderBackground = color.RGBA{R: 0x2b, G: 0x2b, B: 0x2b, A: 0xff}
+	placeholderFrame      = color.RGBA{R: 0x44, G: 0x44, B: 0x44, A: 0xff}
+)
+
+// placeholder is a plain framed 2:3 poster, made once per width
+func (usecase *useCase) placeholder(width int) (Image, error) {
+	if width == 0 {
+		width = placeholderWidth
+	}
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	if data, ok := usecase.placeholders[width]; ok {
+		return Image{Data: data, ContentType: "image/jpeg", Placeholder: true}, nil
+	}
+	height := width * 3 / 2
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(placeholderBackground), image.Point{}, draw.Src)
+	border := max(1, width/25)
+	frame := image.Rect(border*2, border*2, width-border*2, height-border*2)
+	draw.Draw(img, frame, image.NewUniform(placeholderFrame), image.Point{}, draw.Src)
+	draw.Draw(img, frame.Inset(border), image.NewUniform(placeholderBackground), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return Image{}, err
+	}
+	usecase.placeholders[width] = buf.Bytes()
+	return Image{Data: buf.Bytes(), ContentType: "image/jpeg", Placeholder: true}, nil
+}
@@ -0,0 +1,10 @@
+package poster
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface) {
+	MovieRouter := r.Group("/")
+	MovieRouter.GET("movie/:id/poster", h.GetPoster)
+}
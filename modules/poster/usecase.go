@@ -0,0 +1,286 @@
+package poster
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"mime"
+	"movie-app-go/repositories"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// widthStep rounds requested widths up so a handful of variants serve
+	// every client
+	widthStep   = 50
+	jpegQuality = 85
+)
+
+var (
+	ErrMovieNotFound   = errors.New("MOVIE_NOT_FOUND")
+	ErrFetchFailed     = errors.New("POSTER_FETCH_FAILED")
+	ErrTooLarge        = errors.New("POSTER_TOO_LARGE")
+	ErrUnsupportedType = errors.New("UNSUPPORTED_POSTER_TYPE")
+)
+
+// allowedTypes are the image formats accepted from poster hosts, checked
+// against both the declared and the sniffed content type
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Limits bound what the proxy fetches and serves
+type Limits struct {
+	CacheDir     string
+	MaxBytes     int64
+	MaxPixels    int
+	MaxWidth     int
+	FetchTimeout time.Duration
+	// RetryAfter keeps a poster that failed to fetch on the placeholder
+	// for a while instead of hitting its host on every request
+	RetryAfter time.Duration
+}
+
+// Image is an encoded poster ready to serve
+type Image struct {
+	Data        []byte
+	ContentType string
+	Placeholder bool
+}
+
+type useCase struct {
+	movieRepo repositories.MovieRepositoryInterface
+	client    *http.Client
+	limits    Limits
+
+	// mu guards locks, failures and placeholders. Work on one poster is
+	// serialised by its own lock so it is fetched and resized once
+	mu           sync.Mutex
+	locks        map[string]*sync.Mutex
+	failures     map[string]time.Time
+	placeholders map[int][]byte
+}
+
+type UseCaseInterface interface {
+	Get(movieID, width int) (Image, error)
+}
+
+func NewUseCase(movieRepo repositories.MovieRepositoryInterface, limits Limits) UseCaseInterface {
+	return &useCase{
+		movieRepo:    movieRepo,
+		client:       &http.Client{Timeout: limits.FetchTimeout},
+		limits:       limits,
+		locks:        make(map[string]*sync.Mutex),
+		failures:     make(map[string]time.Time),
+		placeholders: make(map[int][]byte),
+	}
+}
+
+// Get returns the movie's poster as a JPEG width pixels wide, or the full
+// size for zero, never wider than the original or Limits.MaxWidth. The
+// original is fetched once and kept on disk along with each variant. A
+// poster that cannot be fetched or decoded is replaced by a placeholder
+func (usecase *useCase) Get(movieID, width int) (Image, error) {
+	movie, err := usecase.movieRepo.Read(movieID)
+	if err != nil {
+		return Image{}, ErrMovieNotFound
+	}
+	width = usecase.snap(width)
+
+	img, err := usecase.variant(movie.Poster_url, width)
+	if err != nil {
+		slog.Warn("UseCase.Poster.Get.01", "error", err, "movie_id", movieID)
+		return usecase.placeholder(width)
+	}
+	return img, nil
+}
+
+// snap rounds the width up to the next step within the maximum
+func (usecase *useCase) snap(width int) int {
+	if width <= 0 {
+		return 0
+	}
+	width = (width + widthStep - 1) / widthStep * widthStep
+	return min(width, usecase.limits.MaxWidth)
+}
+
+func (usecase *useCase) variant(rawURL string, width int) (Image, error) {
+	key := cacheKey(rawURL)
+	path := filepath.Join(usecase.limits.CacheDir, "variants", fmt.Sprintf("%s-%d.jpg", key, width))
+	if data, err := os.ReadFile(path); err == nil {
+		return Image{Data: data, ContentType: "image/jpeg"}, nil
+	}
+
+	unlock := usecase.lock(key)
+	defer unlock()
+	// Another request may have made it while we waited
+	if data, err := os.ReadFile(path); err == nil {
+		return Image{Data: data, ContentType: "image/jpeg"}, nil
+	}
+
+	original, err := usecase.original(rawURL, key)
+	if err != nil {
+		return Image{}, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(original))
+	if err != nil {
+		return Image{}, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+	if width == 0 {
+		width = usecase.limits.MaxWidth
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resize(src, width), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return Image{}, err
+	}
+	if err := writeFile(path, buf.Bytes()); err != nil {
+		slog.Error("UseCase.Poster.Variant.01", "error", err, "path", path)
+	}
+	return Image{Data: buf.Bytes(), ContentType: "image/jpeg"}, nil
+}
+
+// original reads the cached original, fetching it on first use. Callers
+// hold the poster's lock
+func (usecase *useCase) original(rawURL, key string) ([]byte, error) {
+	path := filepath.Join(usecase.limits.CacheDir, "originals", key)
+	if data, err := os.ReadFile(path); err == nil {
+		return data, nil
+	}
+
+	usecase.mu.Lock()
+	retryAt := usecase.failures[key]
+	usecase.mu.Unlock()
+	if time.Now().Before(retryAt) {
+		return nil, fmt.Errorf("%w: retrying after %s", ErrFetchFailed, retryAt.Format(time.RFC3339))
+	}
+
+	data, err := usecase.fetch(rawURL)
+	if err != nil {
+		usecase.mu.Lock()
+		usecase.failures[key] = time.Now().Add(usecase.limits.RetryAfter)
+		usecase.mu.Unlock()
+		return nil, err
+	}
+	if err := writeFile(path, data); err != nil {
+		slog.Error("UseCase.Poster.Original.01", "error", err, "path", path)
+	}
+	return data, nil
+}
+
+// fetch downloads a poster, refusing anything that is not a reasonably
+// sized image in one of the allowed formats
+func (usecase *useCase) fetch(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: invalid poster url %q", ErrFetchFailed, rawURL)
+	}
+	resp, err := usecase.client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrFetchFailed, resp.StatusCode)
+	}
+	if declared, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); !allowedTypes[declared] {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedType, resp.Header.Get("Content-Type"))
+	}
+	if resp.ContentLength > usecase.limits.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLarge, resp.ContentLength)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, usecase.limits.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	if int64(len(data)) > usecase.limits.MaxBytes {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrTooLarge, usecase.limits.MaxBytes)
+	}
+	if sniffed := http.DetectContentType(data); !allowedTypes[sniffed] {
+		return nil, fmt.Errorf("%w: content is %q", ErrUnsupportedType, sniffed)
+	}
+	// Check the dimensions before anything decodes the whole image
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > usecase.limits.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, config.Width, config.Height)
+	}
+	return data, nil
+}
+
+// lock takes the poster's lock and returns its unlock
+func (usecase *useCase) lock(key string) func() {
+	usecase.mu.Lock()
+	l, ok := usecase.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		usecase.locks[key] = l
+	}
+	usecase.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// resize scales the image down to width keeping its aspect ratio, it never
+// scales up. JPEG has no transparency, so transparent areas turn white
+func resize(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	width = min(width, bounds.Dx())
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
+
+// cacheKey names a poster's cache files after its URL, so a changed URL is
+// fetched afresh
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:16])
+}
+
+// writeFile replaces path in one step so readers never see half a file
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,249 @@
+package poster
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+)
+
+const movieID = 1
+
+func testLimits(t *testing.T) Limits {
+	return Limits{
+		CacheDir:     t.TempDir(),
+		MaxBytes:     1 << 20,
+		MaxPixels:    1000 * 1000,
+		MaxWidth:     500,
+		FetchTimeout: time.Second,
+		RetryAfter:   time.Minute,
+	}
+}
+
+// serve starts a poster host answering with handler and returns a use case
+// whose only movie points at it, along with a count of upstream requests
+func serve(t *testing.T, limits Limits, handler http.HandlerFunc) (*useCase, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	movieRepo := repositories.NewMovieRepository([]entities.Movie{
+		{ID: movieID, Title: "Poster Test", Poster_url: server.URL + "/poster"},
+	})
+	return NewUseCase(movieRepo, limits).(*useCase), &hits
+}
+
+func pngPoster(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, height/2, color.RGBA{R: 0xff, A: 0xff})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pixelBomb is a valid PNG header claiming a huge image, the kind of file
+// that is tiny on the wire but exhausts memory once decoded
+func pixelBomb() []byte {
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], 50000)
+	binary.BigEndian.PutUint32(ihdr[4:], 50000)
+	ihdr[8], ihdr[9] = 8, 2 // 8-bit RGB
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr...)
+	buf.Write(chunk)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	return buf.Bytes()
+}
+
+func image200(data []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}
+}
+
+func TestGetCachesOriginalsAndVariants(t *testing.T) {
+	usecase, hits := serve(t, testLimits(t), image200(pngPoster(t, 400, 600)))
+
+	tests := []struct {
+		name      string
+		width     int
+		wantWidth int
+		wantHits  int32
+	}{
+		{name: "miss fetches the original", width: 120, wantWidth: 150, wantHits: 1},
+		{name: "hit serves the cached variant", width: 150, wantWidth: 150, wantHits: 1},
+		{name: "new width reuses the cached original", width: 200, wantWidth: 200, wantHits: 1},
+		{name: "full size never scales up", width: 0, wantWidth: 400, wantHits: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := usecase.Get(movieID, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Placeholder || img.ContentType != "image/jpeg" {
+				t.Fatalf("got placeholder %v, content type %q", img.Placeholder, img.ContentType)
+			}
+			config, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Width != tt.wantWidth {
+				t.Errorf("width = %d, want %d", config.Width, tt.wantWidth)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("upstream requests = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+
+	if _, err := usecase.Get(movieID+1, 100); !errors.Is(err, ErrMovieNotFound) {
+		t.Errorf("unknown movie error = %v, want %v", err, ErrMovieNotFound)
+	}
+}
+
+func TestFetchRejectsUnsafePosters(t *testing.T) {
+	poster := pngPoster(t, 40, 60)
+
+	tests := []struct {
+		name    string
+		limits  func(*Limits)
+		handler http.HandlerFunc
+		wantErr error
+	}{
+		{
+			name: "declared type not an image",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write(poster)
+			},
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name: "content not the declared image",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				w.Write([]byte("<html><body>not a poster</body></html>"))
+			},
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:   "content length over the limit",
+			limits: func(l *Limits) { l.MaxBytes = int64(len(poster)) - 1 },
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				w.Header().Set("Content-Length", strconv.Itoa(len(poster)))
+				w.Write(poster)
+			},
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:   "body over the limit without a length",
+			limits: func(l *Limits) { l.MaxBytes = int64(len(poster)) - 1 },
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				// Flushing first makes the response chunked
+				w.(http.Flusher).Flush()
+				w.Write(poster)
+			},
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "pixel bomb",
+			handler: image200(pixelBomb()),
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "upstream error",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			wantErr: ErrFetchFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := testLimits(t)
+			if tt.limits != nil {
+				tt.limits(&limits)
+			}
+			usecase, _ := serve(t, limits, tt.handler)
+			movie, _ := usecase.movieRepo.Read(movieID)
+
+			if _, err := usecase.fetch(movie.Poster_url); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			img, err := usecase.Get(movieID, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !img.Placeholder {
+				t.Error("rejected poster was served instead of the placeholder")
+			}
+		})
+	}
+}
+
+func TestGetHonoursRetryAfter(t *testing.T) {
+	limits := testLimits(t)
+	limits.RetryAfter = 200 * time.Millisecond
+
+	var healthy atomic.Bool
+	poster := pngPoster(t, 40, 60)
+	usecase, hits := serve(t, limits, func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		image200(poster)(w, r)
+	})
+
+	img, err := usecase.Get(movieID, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !img.Placeholder {
+		t.Fatal("failed fetch did not serve the placeholder")
+	}
+
+	// The host recovers, but the poster stays on the placeholder until
+	// RetryAfter passes
+	healthy.Store(true)
+	for i := 0; i < 3; i++ {
+		if img, _ := usecase.Get(movieID, 100); !img.Placeholder {
+			t.Fatal("poster refetched within RetryAfter")
+		}
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("upstream requests within RetryAfter = %d, want 1", got)
+	}
+
+	time.Sleep(limits.RetryAfter)
+	if img, _ := usecase.Get(movieID, 100); img.Placeholder {
+		t.Fatal("poster still on the placeholder after RetryAfter")
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("upstream requests after RetryAfter = %d, want 2", got)
+	}
+}
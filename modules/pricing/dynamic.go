@@ -0,0 +1,156 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"movie-app-go/entities"
+)
+
+// Demand rule variables
+const (
+	varOccupancy = "occupancy"
+	varHours     = "hours"
+)
+
+type condition struct {
+	variable string
+	operator string
+	value    float64
+}
+
+// Rule multiplies the ticket price when all of its conditions hold
+type Rule struct {
+	conditions []condition
+	Multiplier float64
+	source     string
+}
+
+func (r Rule) String() string {
+	return r.source
+}
+
+// ParseRules reads demand rules written as conditions joined by "&", a colon
+// and the multiplier, e.g. "occupancy>=0.8&hours<=24:1.3". occupancy is the
+// booked or held share of the seats (0 to 1) and hours the time left until
+// the showtime
+func ParseRules(specs []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		conditions, multiplier, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("demand rule %q: missing \":<multiplier>\"", spec)
+		}
+		rule := Rule{source: spec}
+		m, err := strconv.ParseFloat(strings.TrimSpace(multiplier), 64)
+		if err != nil || m <= 0 {
+			return nil, fmt.Errorf("demand rule %q: invalid multiplier", spec)
+		}
+		rule.Multiplier = m
+
+		for _, part := range strings.Split(conditions, "&") {
+			cond, err := parseCondition(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("demand rule %q: %w", spec, err)
+			}
+			rule.conditions = append(rule.conditions, cond)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseCondition(text string) (condition, error) {
+	for _, operator := range []string{"<=", ">=", "<", ">"} {
+		variable, value, ok := strings.Cut(text, operator)
+		if !ok {
+			continue
+		}
+		variable = strings.TrimSpace(variable)
+		if variable != varOccupancy && variable != varHours {
+			return condition{}, fmt.Errorf("unknown variable %q", variable)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return condition{}, fmt.Errorf("invalid value in %q", text)
+		}
+		return condition{variable: variable, operator: operator, value: v}, nil
+	}
+	return condition{}, fmt.Errorf("invalid condition %q", text)
+}
+
+func (c condition) holds(occupancy, hours float64) bool {
+	x := occupancy
+	if c.variable == varHours {
+		x = hours
+	}
+	switch c.operator {
+	case "<":
+		return x < c.value
+	case "<=":
+		return x <= c.value
+	case ">":
+		return x > c.value
+	}
+	return x >= c.value
+}
+
+// Demand describes how busy a showing is when a price is quoted
+type Demand struct {
+	Occupancy       float64 `json:"occupancy"`
+	HoursToShowtime float64 `json:"hours_to_showtime"`
+	Multiplier      float64 `json:"multiplier"`
+}
+
+// Occupancy is the share of bookable seats that are booked or held
+func Occupancy(m entities.Movie, at time.Time) float64 {
+	total, taken := 0, 0
+	for _, seat := range m.Seats {
+		switch seat.Status(at) {
+		case entities.SeatBlocked:
+			continue
+		case entities.SeatBooked, entities.SeatHeld:
+			taken++
+		}
+		total++
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(taken) / float64(total)
+}
+
+// demand applies every matching rule in turn and clamps the result
+func (svc *service) demand(m entities.Movie, at time.Time) Demand {
+	d := Demand{
+		Occupancy:  Occupancy(m, at),
+		Multiplier: 1,
+	}
+	if !m.Showtime.IsZero() {
+		d.HoursToShowtime = math.Max(0, m.Showtime.Sub(at).Hours())
+	}
+	for _, rule := range svc.rules {
+		matched := true
+		for _, cond := range rule.conditions {
+			if cond.variable == varHours && m.Showtime.IsZero() {
+				matched = false
+				break
+			}
+			if !cond.holds(d.Occupancy, d.HoursToShowtime) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			d.Multiplier *= rule.Multiplier
+		}
+	}
+	d.Multiplier = math.Min(math.Max(d.Multiplier, svc.minDemand), svc.maxDemand)
+	d.Multiplier = math.Round(d.Multiplier*100) / 100
+
+	return d
+}
@@ -0,0 +1,119 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"movie-app-go/entities"
+)
+
+type service struct {
+	multipliers map[string]float64
+	rules       []Rule
+	minDemand   float64
+	maxDemand   float64
+}
+
+type ServiceInterface interface {
+	SeatPrice(m entities.Movie, s entities.Seat) (int, float64)
+	Quote(m entities.Movie, seats []entities.Seat) entities.PriceBreakdown
+	CurrentQuote(m entities.Movie) MovieQuote
+}
+
+// MovieQuote is the price of each seat type of a movie right now
+type MovieQuote struct {
+	MovieID  int            `json:"movie_id"`
+	Demand   Demand         `json:"demand"`
+	Prices   map[string]int `json:"prices"`
+	QuotedAt time.Time      `json:"quoted_at"`
+}
+
+// NewService prices seats by type, then scales the ticket price with the
+// demand rules, clamped to [minDemand, maxDemand]. Types missing from
+// multipliers, and any unknown type, are charged the plain ticket price
+func NewService(multipliers map[string]float64, rules []Rule, minDemand, maxDemand float64) ServiceInterface {
+	return &service{
+		multipliers: multipliers,
+		rules:       rules,
+		minDemand:   minDemand,
+		maxDemand:   maxDemand,
+	}
+}
+
+// SeatPrice returns the current price of one seat and the multiplier applied
+// to the movie's ticket price for its type
+func (svc *service) SeatPrice(m entities.Movie, s entities.Seat) (int, float64) {
+	d := svc.demand(m, time.Now())
+	_, multiplier, price := svc.seatPrice(m, s, d.Multiplier)
+	return price, multiplier
+}
+
+// Quote builds the line-item cost of a set of seats
+func (svc *service) Quote(m entities.Movie, seats []entities.Seat) entities.PriceBreakdown {
+	now := time.Now()
+	d := svc.demand(m, now)
+	breakdown := entities.PriceBreakdown{
+		Items:            make([]entities.PriceLine, 0, len(seats)),
+		DemandMultiplier: d.Multiplier,
+		QuotedAt:         now,
+	}
+	for _, seat := range seats {
+		base, multiplier, price := svc.seatPrice(m, seat, d.Multiplier)
+		seatType := seat.Type
+		if seatType == "" {
+			seatType = entities.SeatRegular
+		}
+		breakdown.Items = append(breakdown.Items, entities.PriceLine{
+			Seat:       fmt.Sprintf("%s%d", seat.Row, seat.Number),
+			Type:       seatType,
+			BasePrice:  base,
+			Multiplier: multiplier,
+			Price:      price,
+		})
+		breakdown.Subtotal += price
+	}
+	breakdown.Total = breakdown.Subtotal
+
+	return breakdown
+}
+
+func (svc *service) CurrentQuote(m entities.Movie) MovieQuote {
+	now := time.Now()
+	d := svc.demand(m, now)
+	quote := MovieQuote{
+		MovieID:  m.ID,
+		Demand:   d,
+		Prices:   make(map[string]int),
+		QuotedAt: now,
+	}
+	for _, seat := range m.Seats {
+		if _, ok := quote.Prices[seat.Type]; ok {
+			continue
+		}
+		_, _, price := svc.seatPrice(m, seat, d.Multiplier)
+		quote.Prices[seat.Type] = price
+	}
+	return quote
+}
+
+// seatPrice scales the ticket price by demand, then applies the seat type
+// multiplier. A per-movie override replaces the default multiplier, a fixed
+// override price is scaled by demand as well
+func (svc *service) seatPrice(m entities.Movie, s entities.Seat, demand float64) (int, float64, int) {
+	base := int(math.Round(float64(m.Ticket_price) * demand))
+	multiplier := 1.0
+	if mul, ok := svc.multipliers[s.Type]; ok {
+		multiplier = mul
+	}
+	if override, ok := m.Seat_pricing[s.Type]; ok {
+		if override.Price > 0 {
+			multiplier = float64(override.Price) / math.Max(1, float64(m.Ticket_price))
+			return base, multiplier, int(math.Round(float64(override.Price) * demand))
+		}
+		if override.Multiplier > 0 {
+			multiplier = override.Multiplier
+		}
+	}
+	return base, multiplier, int(math.Round(float64(base) * multiplier))
+}
@@ -0,0 +1,27 @@
+package promo
+
+import "time"
+
+type (
+	CreatePromoRequest struct {
+		Code           string    `json:"code" binding:"required,alphanum,min=3,max=32"`
+		Type           string    `json:"type" binding:"required,oneof=percentage fixed"`
+		Value          int       `json:"value" binding:"required,min=1"`
+		Max_discount   int       `json:"max_discount" binding:"min=0"`
+		Min_spend      int       `json:"min_spend" binding:"min=0"`
+		Per_user_limit int       `json:"per_user_limit" binding:"min=0"`
+		Global_limit   int       `json:"global_limit" binding:"min=0"`
+		Valid_from     time.Time `json:"valid_from"`
+		Valid_until    time.Time `json:"valid_until"`
+		Movie_ids      []int     `json:"movie_ids"`
+	}
+	StatusRequest struct {
+		Active *bool `json:"active" binding:"required"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
@@ -0,0 +1,151 @@
+package promo
+
+import (
+	"errors"
+	"net/http"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	promoUseCase UseCaseInterface
+}
+
+type HandlerInterface interface {
+	CreatePromo(c *gin.Context)
+	GetPromos(c *gin.Context)
+	GetRedemptions(c *gin.Context)
+	UpdateStatus(c *gin.Context)
+}
+
+func NewHandler(promoUseCase UseCaseInterface) HandlerInterface {
+	return &handler{
+		promoUseCase: promoUseCase,
+	}
+}
+
+func (h handler) CreatePromo(c *gin.Context) {
+	var req CreatePromoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.CreatePromo.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	promo, err := h.promoUseCase.Create(entities.PromoCode{
+		Code:           req.Code,
+		Type:           req.Type,
+		Value:          req.Value,
+		Max_discount:   req.Max_discount,
+		Min_spend:      req.Min_spend,
+		Per_user_limit: req.Per_user_limit,
+		Global_limit:   req.Global_limit,
+		Valid_from:     req.Valid_from,
+		Valid_until:    req.Valid_until,
+		Movie_ids:      req.Movie_ids,
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Handler.CreatePromo.02", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Code:    http.StatusCreated,
+		Message: "CREATED_PROMO",
+		Data:    promo,
+	})
+}
+
+func (h handler) GetPromos(c *gin.Context) {
+	promos, err := h.promoUseCase.GetAll()
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetPromos.01", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    promos,
+	})
+}
+
+func (h handler) GetRedemptions(c *gin.Context) {
+	redemptions, err := h.promoUseCase.GetRedemptions(c.Param("code"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetRedemptions.01", "error", err)
+
+		c.JSON(http.StatusNotFound, Response{
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    redemptions,
+	})
+}
+
+func (h handler) UpdateStatus(c *gin.Context) {
+	var req StatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.UpdateStatus.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	promo, err := h.promoUseCase.SetActive(c.Param("code"), *req.Active)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.UpdateStatus.02", "error", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrPromoNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    promo,
+	})
+}
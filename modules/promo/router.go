@@ -0,0 +1,13 @@
+package promo
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc, adminOnly gin.HandlerFunc) {
+	PromoRouter := r.Group("/admin/promos", middleware, adminOnly)
+	PromoRouter.POST("", h.CreatePromo)
+	PromoRouter.GET("", h.GetPromos)
+	PromoRouter.GET("/:code/redemptions", h.GetRedemptions)
+	PromoRouter.PATCH("/:code", h.UpdateStatus)
+}
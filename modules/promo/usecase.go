@@ -0,0 +1,239 @@
+package promo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPromoNotFound    = errors.New("PROMO_NOT_FOUND")
+	ErrPromoInactive    = errors.New("PROMO_INACTIVE")
+	ErrPromoNotStarted  = errors.New("PROMO_NOT_STARTED")
+	ErrPromoExpired     = errors.New("PROMO_EXPIRED")
+	ErrPromoMinSpend    = errors.New("PROMO_MIN_SPEND_NOT_MET")
+	ErrPromoMovie       = errors.New("PROMO_NOT_VALID_FOR_MOVIE")
+	ErrPromoUsageLimit  = errors.New("PROMO_USAGE_LIMIT_REACHED")
+	ErrPromoUserLimit   = errors.New("PROMO_USER_LIMIT_REACHED")
+	ErrPromoInvalidType = errors.New("PROMO_INVALID_TYPE")
+	ErrPromoInvalidRate = errors.New("PROMO_PERCENTAGE_OUT_OF_RANGE")
+	ErrPromoInvalidSpan = errors.New("PROMO_INVALID_VALIDITY_WINDOW")
+)
+
+type useCase struct {
+	// mu serialises redemptions so usage caps hold under concurrent checkouts
+	mu        sync.Mutex
+	promoRepo repositories.PromoRepositoryInterface
+}
+
+type UseCaseInterface interface {
+	Create(p entities.PromoCode) (entities.PromoCode, error)
+	GetAll() ([]entities.PromoCode, error)
+	GetRedemptions(code string) ([]entities.PromoRedemption, error)
+	SetActive(code string, active bool) (entities.PromoCode, error)
+	Evaluate(code string, u entities.User, m entities.Movie, subtotal int) (entities.PromoCode, int, error)
+	Redeem(code string, u entities.User, m entities.Movie, ticketID string, subtotal int) (int, error)
+	Release(ticketID string) error
+}
+
+func NewUseCase(promoRepo repositories.PromoRepositoryInterface) UseCaseInterface {
+	return &useCase{
+		promoRepo: promoRepo,
+	}
+}
+
+// NormalizeCode makes codes case-insensitive
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (usecase *useCase) Create(p entities.PromoCode) (entities.PromoCode, error) {
+	p.Code = NormalizeCode(p.Code)
+	switch p.Type {
+	case entities.PromoPercentage:
+		if p.Value > 100 {
+			return entities.PromoCode{}, ErrPromoInvalidRate
+		}
+	case entities.PromoFixed:
+	default:
+		return entities.PromoCode{}, ErrPromoInvalidType
+	}
+	if !p.Valid_until.IsZero() && p.Valid_until.Before(p.Valid_from) {
+		return entities.PromoCode{}, ErrPromoInvalidSpan
+	}
+	p.Used = 0
+	p.Active = true
+	p.Created_at = time.Now()
+	p.Updated_at = time.Now()
+
+	if err := usecase.promoRepo.Create(p); err != nil {
+		return entities.PromoCode{}, err
+	}
+	return p, nil
+}
+
+func (usecase *useCase) GetAll() ([]entities.PromoCode, error) {
+	return usecase.promoRepo.ReadAll()
+}
+
+func (usecase *useCase) GetRedemptions(code string) ([]entities.PromoRedemption, error) {
+	code = NormalizeCode(code)
+	if _, err := usecase.promoRepo.Read(code); err != nil {
+		return nil, ErrPromoNotFound
+	}
+	return usecase.promoRepo.ReadRedemptions(code)
+}
+
+func (usecase *useCase) SetActive(code string, active bool) (entities.PromoCode, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	p, err := usecase.promoRepo.Read(NormalizeCode(code))
+	if err != nil {
+		return entities.PromoCode{}, ErrPromoNotFound
+	}
+	p.Active = active
+	if err := usecase.promoRepo.Update(p); err != nil {
+		return entities.PromoCode{}, err
+	}
+	return p, nil
+}
+
+// Evaluate checks that the user may use the code on the movie right now and
+// returns the discount it gives on the subtotal, without redeeming it
+func (usecase *useCase) Evaluate(code string, u entities.User, m entities.Movie, subtotal int) (entities.PromoCode, int, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	return usecase.evaluate(NormalizeCode(code), u, m, subtotal, time.Now())
+}
+
+// Redeem evaluates the code again and records its use by the ticket in one
+// step, so two checkouts can never both take the last use
+func (usecase *useCase) Redeem(code string, u entities.User, m entities.Movie, ticketID string, subtotal int) (int, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	p, discount, err := usecase.evaluate(NormalizeCode(code), u, m, subtotal, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		return 0, err
+	}
+	p.Used++
+	if err := usecase.promoRepo.Update(p); err != nil {
+		return 0, err
+	}
+	if err := usecase.promoRepo.CreateRedemption(entities.PromoRedemption{
+		ID:         UUID.String(),
+		Code:       p.Code,
+		UserID:     u.ID,
+		TicketID:   ticketID,
+		Discount:   discount,
+		Created_at: time.Now(),
+	}); err != nil {
+		return 0, err
+	}
+	return discount, nil
+}
+
+// Release gives back the use taken by a ticket, tickets bought without a
+// code are ignored
+func (usecase *useCase) Release(ticketID string) error {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	redemption, err := usecase.promoRepo.DeleteRedemptionByTicket(ticketID)
+	if err != nil {
+		return nil
+	}
+	p, err := usecase.promoRepo.Read(redemption.Code)
+	if err != nil {
+		return err
+	}
+	if p.Used > 0 {
+		p.Used--
+	}
+	return usecase.promoRepo.Update(p)
+}
+
+func (usecase *useCase) evaluate(code string, u entities.User, m entities.Movie, subtotal int, at time.Time) (entities.PromoCode, int, error) {
+	p, err := usecase.promoRepo.Read(code)
+	if err != nil {
+		return entities.PromoCode{}, 0, ErrPromoNotFound
+	}
+	switch {
+	case !p.Active:
+		return p, 0, ErrPromoInactive
+	case !p.Valid_from.IsZero() && at.Before(p.Valid_from):
+		return p, 0, ErrPromoNotStarted
+	case !p.Valid_until.IsZero() && at.After(p.Valid_until):
+		return p, 0, ErrPromoExpired
+	case subtotal < p.Min_spend:
+		return p, 0, ErrPromoMinSpend
+	case p.Global_limit > 0 && p.Used >= p.Global_limit:
+		return p, 0, ErrPromoUsageLimit
+	}
+	if len(p.Movie_ids) > 0 && !containsMovie(p.Movie_ids, m.ID) {
+		return p, 0, ErrPromoMovie
+	}
+	if p.Per_user_limit > 0 {
+		redemptions, err := usecase.promoRepo.ReadRedemptions(p.Code)
+		if err != nil {
+			return p, 0, err
+		}
+		used := 0
+		for _, redemption := range redemptions {
+			if redemption.UserID == u.ID {
+				used++
+			}
+		}
+		if used >= p.Per_user_limit {
+			return p, 0, ErrPromoUserLimit
+		}
+	}
+
+	return p, Discount(p, subtotal), nil
+}
+
+// Discount computes what the code takes off the subtotal, never more than
+// the subtotal itself
+func Discount(p entities.PromoCode, subtotal int) int {
+	discount := p.Value
+	if p.Type == entities.PromoPercentage {
+		discount = subtotal * p.Value / 100
+		if p.Max_discount > 0 && discount > p.Max_discount {
+			discount = p.Max_discount
+		}
+	}
+	if discount > subtotal {
+		discount = subtotal
+	}
+	return discount
+}
+
+// Describe renders a short label for the discount line of a receipt
+func Describe(p entities.PromoCode) string {
+	if p.Type == entities.PromoPercentage {
+		return fmt.Sprintf("%s: %d%% off", p.Code, p.Value)
+	}
+	return fmt.Sprintf("%s: %d off", p.Code, p.Value)
+}
+
+func containsMovie(ids []int, id int) bool {
+	for _, movieID := range ids {
+		if movieID == id {
+			return true
+		}
+	}
+	return false
+}
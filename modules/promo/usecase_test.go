@@ -0,0 +1,128 @@
+package promo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+)
+
+var (
+	alice = entities.User{ID: "u-alice", Username: "alice"}
+	bob   = entities.User{ID: "u-bob", Username: "bob"}
+	movie = entities.Movie{ID: 1}
+)
+
+func TestDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		promo    entities.PromoCode
+		subtotal int
+		want     int
+	}{
+		{name: "percentage", promo: entities.PromoCode{Type: entities.PromoPercentage, Value: 10}, subtotal: 50000, want: 5000},
+		{name: "percentage capped", promo: entities.PromoCode{Type: entities.PromoPercentage, Value: 50, Max_discount: 10000}, subtotal: 50000, want: 10000},
+		{name: "fixed", promo: entities.PromoCode{Type: entities.PromoFixed, Value: 15000}, subtotal: 50000, want: 15000},
+		{name: "fixed above subtotal", promo: entities.PromoCode{Type: entities.PromoFixed, Value: 15000}, subtotal: 9000, want: 9000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Discount(tt.promo, tt.subtotal); got != tt.want {
+				t.Errorf("Discount = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedeemCaps(t *testing.T) {
+	tests := []struct {
+		name  string
+		promo entities.PromoCode
+		// redeemers use the code one after another, released gives back
+		// the use of the ticket at that index before the next one redeems
+		redeemers []entities.User
+		released  int
+		want      []error
+	}{
+		{
+			name:      "global limit",
+			promo:     entities.PromoCode{Global_limit: 2},
+			redeemers: []entities.User{alice, bob, bob},
+			released:  -1,
+			want:      []error{nil, nil, ErrPromoUsageLimit},
+		},
+		{
+			name:      "per user limit",
+			promo:     entities.PromoCode{Per_user_limit: 1},
+			redeemers: []entities.User{alice, alice, bob},
+			released:  -1,
+			want:      []error{nil, ErrPromoUserLimit, nil},
+		},
+		{
+			name:      "released use is given back",
+			promo:     entities.PromoCode{Global_limit: 1, Per_user_limit: 1},
+			redeemers: []entities.User{alice, alice},
+			released:  0,
+			want:      []error{nil, nil},
+		},
+		{
+			name:      "minimum spend",
+			promo:     entities.PromoCode{Min_spend: 60000},
+			redeemers: []entities.User{alice},
+			released:  -1,
+			want:      []error{ErrPromoMinSpend},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := NewUseCase(repositories.NewPromoRepository(nil))
+			tt.promo.Code, tt.promo.Type, tt.promo.Value = "save10", entities.PromoPercentage, 10
+			if _, err := usecase.Create(tt.promo); err != nil {
+				t.Fatal(err)
+			}
+			for i, u := range tt.redeemers {
+				_, err := usecase.Redeem("SAVE10", u, movie, fmt.Sprintf("t-%d", i), 50000)
+				if !errors.Is(err, tt.want[i]) {
+					t.Fatalf("redemption %d by %s: error = %v, want %v", i, u.Username, err, tt.want[i])
+				}
+				if i == tt.released {
+					if err := usecase.Release(fmt.Sprintf("t-%d", i)); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRedeemTakesTheLastUseOnce(t *testing.T) {
+	usecase := NewUseCase(repositories.NewPromoRepository(nil))
+	if _, err := usecase.Create(entities.PromoCode{Code: "LAST", Type: entities.PromoFixed, Value: 1000, Global_limit: 1}); err != nil {
+		t.Fatal(err)
+	}
+	const buyers = 32
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		winners int
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			u := entities.User{ID: fmt.Sprintf("u-%d", i)}
+			if _, err := usecase.Redeem("LAST", u, movie, fmt.Sprintf("t-%d", i), 50000); err == nil {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Errorf("%d checkouts took the last use", winners)
+	}
+}
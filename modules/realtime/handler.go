@@ -0,0 +1,98 @@
+package realtime
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"movie-app-go/modules/logger"
+	"movie-app-go/repositories"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+type handler struct {
+	hub       HubInterface
+	movieRepo repositories.MovieRepositoryInterface
+}
+
+type HandlerInterface interface {
+	StreamSeats(c *gin.Context)
+}
+
+type Response struct {
+	Code      int    `json:"code" binding:"required"`
+	Message   string `json:"message" binding:"required"`
+	Data      any    `json:"data" binding:"required"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func NewHandler(hub HubInterface, movieRepo repositories.MovieRepositoryInterface) HandlerInterface {
+	return &handler{
+		hub:       hub,
+		movieRepo: movieRepo,
+	}
+}
+
+func (h handler) StreamSeats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.StreamSeats.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_CONVERT_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if _, err := h.movieRepo.Read(id); err != nil {
+		logger.FromContext(c).Error("Handler.StreamSeats.02", "error", err)
+
+		c.JSON(http.StatusNotFound, Response{
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	events, unsubscribe := h.hub.Subscribe(id)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("ready", gin.H{"movie_id": id})
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind, the client has to resync
+				c.SSEvent("reset", gin.H{"movie_id": id})
+				return false
+			}
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatUint(event.ID, 10),
+				Event: event.Type,
+				Data:  event,
+			})
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
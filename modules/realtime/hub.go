@@ -0,0 +1,118 @@
+package realtime
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"movie-app-go/entities"
+)
+
+// Seat event types
+const (
+	SeatBooked   = "seat.booked"
+	SeatReleased = "seat.released"
+	SeatHeld     = "seat.held"
+)
+
+const defaultBuffer = 32
+
+type Event struct {
+	ID      uint64    `json:"id"`
+	Type    string    `json:"type"`
+	MovieID int       `json:"movie_id"`
+	Seats   []string  `json:"seats"`
+	At      time.Time `json:"at"`
+}
+
+type subscriber struct {
+	ch chan Event
+}
+
+// Hub fans seat events out to the subscribers of each movie. Publishing never
+// blocks: a subscriber whose buffer is full is disconnected and is expected
+// to reconnect and reload the seat map
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[int]map[*subscriber]struct{}
+	buffer      int
+	sequence    atomic.Uint64
+}
+
+type HubInterface interface {
+	Publish(eventType string, movieID int, seats []entities.Seat)
+	Subscribe(movieID int) (<-chan Event, func())
+}
+
+func NewHub(buffer int) HubInterface {
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+	return &Hub{
+		subscribers: make(map[int]map[*subscriber]struct{}),
+		buffer:      buffer,
+	}
+}
+
+func (h *Hub) Publish(eventType string, movieID int, seats []entities.Seat) {
+	if len(seats) == 0 {
+		return
+	}
+	event := Event{
+		ID:      h.sequence.Add(1),
+		Type:    eventType,
+		MovieID: movieID,
+		Seats:   make([]string, 0, len(seats)),
+		At:      time.Now(),
+	}
+	for _, seat := range seats {
+		event.Seats = append(event.Seats, fmt.Sprintf("%s%d", seat.Row, seat.Number))
+	}
+
+	var lagging []*subscriber
+	h.mu.RLock()
+	for sub := range h.subscribers[movieID] {
+		select {
+		case sub.ch <- event:
+		default:
+			lagging = append(lagging, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range lagging {
+		h.remove(movieID, sub)
+	}
+}
+
+// Subscribe returns the event channel of a movie and a function that ends
+// the subscription. The channel is closed when the subscription ends,
+// including when the hub drops a slow subscriber
+func (h *Hub) Subscribe(movieID int) (<-chan Event, func()) {
+	sub := &subscriber{ch: make(chan Event, h.buffer)}
+
+	h.mu.Lock()
+	if h.subscribers[movieID] == nil {
+		h.subscribers[movieID] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[movieID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub.ch, func() { h.remove(movieID, sub) }
+}
+
+func (h *Hub) remove(movieID int, sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.subscribers[movieID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, movieID)
+	}
+	close(sub.ch)
+}
@@ -0,0 +1,10 @@
+package realtime
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface) {
+	RealtimeRouter := r.Group("/")
+	RealtimeRouter.GET("movie/:id/events", h.StreamSeats)
+}
@@ -0,0 +1,12 @@
+package recommend
+
+type Request struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+type Response struct {
+	Code      int    `json:"code" binding:"required"`
+	Message   string `json:"message" binding:"required"`
+	Data      any    `json:"data" binding:"required"`
+	RequestID string `json:"request_id,omitempty"`
+}
@@ -0,0 +1,84 @@
+package recommend
+
+import (
+	"net/http"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultLimit = 10
+
+// Accounts resolves the caller of a recommendation route
+type Accounts interface {
+	GetUser(username string) (entities.User, error)
+}
+
+type handler struct {
+	recommendUseCase UseCaseInterface
+	accounts         Accounts
+}
+
+type HandlerInterface interface {
+	GetRecommendations(c *gin.Context)
+}
+
+func NewHandler(recommendUseCase UseCaseInterface, accounts Accounts) HandlerInterface {
+	return &handler{
+		recommendUseCase: recommendUseCase,
+		accounts:         accounts,
+	}
+}
+
+func (h handler) GetRecommendations(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetRecommendations.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BIND_QUERY",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultLimit
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.accounts.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetRecommendations.02", "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	recommendations, err := h.recommendUseCase.ForUser(user, req.Limit, time.Now())
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetRecommendations.03", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    recommendations,
+	})
+}
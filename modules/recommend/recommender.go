@@ -0,0 +1,162 @@
+package recommend
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"movie-app-go/entities"
+)
+
+// Signal weights, what people with the same purchases went on to buy says
+// the most about what a user will buy next
+const (
+	coPurchaseWeight = 0.6
+	genreWeight      = 0.3
+	popularityWeight = 0.1
+)
+
+// Purchase records that a user bought tickets for a movie
+type Purchase struct {
+	UserID  string
+	MovieID int
+}
+
+// Recommendation is a movie the user has not bought, with why it was picked
+type Recommendation struct {
+	entities.Movie
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// Rank orders the movies the user has not bought and eligible accepts.
+// Each scores on three signals, all within 0 and 1:
+//   - co-purchase, how often buyers of the user's movies also bought it,
+//     as the cosine similarity of the two movies' buyers
+//   - genre affinity, the share of the user's movies in its genres
+//   - popularity, its buyers relative to the best selling movie
+//
+// Rank only reads its input, the same input always gives the same order,
+// ties going to the lower movie ID
+func Rank(userID string, movies []entities.Movie, purchases []Purchase, eligible func(entities.Movie) bool) []Recommendation {
+	buyers := make(map[int]map[string]bool)
+	owned := make(map[int]bool)
+	for _, p := range purchases {
+		if buyers[p.MovieID] == nil {
+			buyers[p.MovieID] = make(map[string]bool)
+		}
+		buyers[p.MovieID][p.UserID] = true
+		if p.UserID == userID {
+			owned[p.MovieID] = true
+		}
+	}
+	mostBuyers := 0
+	for _, users := range buyers {
+		mostBuyers = max(mostBuyers, len(users))
+	}
+
+	sorted := make([]entities.Movie, len(movies))
+	copy(sorted, movies)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].ID < sorted[b].ID })
+
+	var history []entities.Movie
+	for _, m := range sorted {
+		if owned[m.ID] {
+			history = append(history, m)
+		}
+	}
+	affinity, genreNames := genreAffinity(history)
+
+	recommendations := make([]Recommendation, 0, len(sorted))
+	for _, m := range sorted {
+		if owned[m.ID] || !eligible(m) {
+			continue
+		}
+		reasons := []string{}
+		coPurchase, bestSimilarity := 0.0, 0.0
+		for _, bought := range history {
+			similarity := cosine(buyers[bought.ID], buyers[m.ID])
+			coPurchase += similarity
+			if similarity > bestSimilarity {
+				bestSimilarity = similarity
+				reasons = append(reasons[:0], "Bought by people who bought "+bought.Title)
+			}
+		}
+		if len(history) > 0 {
+			coPurchase /= float64(len(history))
+		}
+
+		genre, bestAffinity, favourite := 0.0, 0.0, ""
+		for _, g := range m.Genres {
+			key := strings.ToLower(g)
+			genre += affinity[key]
+			if affinity[key] > bestAffinity {
+				bestAffinity, favourite = affinity[key], key
+			}
+		}
+		if len(m.Genres) > 0 {
+			genre /= float64(len(m.Genres))
+		}
+		if favourite != "" {
+			reasons = append(reasons, "Matches your interest in "+genreNames[favourite])
+		}
+
+		popularity := 0.0
+		if mostBuyers > 0 {
+			popularity = float64(len(buyers[m.ID])) / float64(mostBuyers)
+		}
+		if len(reasons) == 0 && popularity > 0 {
+			reasons = append(reasons, "Popular with other moviegoers")
+		}
+
+		score := coPurchaseWeight*coPurchase + genreWeight*genre + popularityWeight*popularity
+		recommendations = append(recommendations, Recommendation{
+			Movie:   m,
+			Score:   math.Round(score*1000) / 1000,
+			Reasons: reasons,
+		})
+	}
+	sort.SliceStable(recommendations, func(a, b int) bool {
+		return recommendations[a].Score > recommendations[b].Score
+	})
+	return recommendations
+}
+
+// genreAffinity is the share of the movies in each genre, keyed by the
+// lowercased genre, with the genre as first written for display
+func genreAffinity(history []entities.Movie) (map[string]float64, map[string]string) {
+	affinity := make(map[string]float64)
+	names := make(map[string]string)
+	for _, m := range history {
+		seen := make(map[string]bool, len(m.Genres))
+		for _, g := range m.Genres {
+			key := strings.ToLower(g)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			affinity[key]++
+			if _, ok := names[key]; !ok {
+				names[key] = g
+			}
+		}
+	}
+	for key := range affinity {
+		affinity[key] /= float64(len(history))
+	}
+	return affinity, names
+}
+
+// cosine is the overlap of two buyer sets relative to their sizes
+func cosine(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	overlap := 0
+	for user := range a {
+		if b[user] {
+			overlap++
+		}
+	}
+	return float64(overlap) / math.Sqrt(float64(len(a))*float64(len(b)))
+}
@@ -0,0 +1,150 @@
+package recommend
+
+import (
+	"reflect"
+	"testing"
+
+	"movie-app-go/entities"
+)
+
+var catalog = []entities.Movie{
+	{ID: 1, Title: "Alpha", Genres: []string{"Action"}},
+	{ID: 2, Title: "Bravo", Genres: []string{"Action", "Thriller"}},
+	{ID: 3, Title: "Charlie", Genres: []string{"Comedy"}},
+	{ID: 4, Title: "Delta", Genres: []string{"Drama"}},
+	{ID: 5, Title: "Echo", Genres: []string{"comedy", "Romance"}},
+}
+
+func purchases(bought map[string][]int) []Purchase {
+	var out []Purchase
+	for _, user := range []string{"me", "u1", "u2", "u3", "u4", "u5"} {
+		for _, movieID := range bought[user] {
+			out = append(out, Purchase{UserID: user, MovieID: movieID})
+		}
+	}
+	return out
+}
+
+func everything(entities.Movie) bool { return true }
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name      string
+		purchases []Purchase
+		eligible  func(entities.Movie) bool
+		wantIDs   []int
+		wantTop   Recommendation
+	}{
+		{
+			name: "co-purchase ranks what buyers of the same movie bought",
+			purchases: purchases(map[string][]int{
+				"me": {1},
+				"u1": {1, 2},
+				"u2": {1, 2},
+				"u3": {1, 3},
+				"u4": {3, 4},
+				"u5": {4},
+			}),
+			eligible: everything,
+			wantIDs:  []int{2, 3, 4, 5},
+			wantTop: Recommendation{
+				Movie: catalog[1],
+				// 0.6*cos(4 buyers, 2 buyers, 2 shared) + 0.3*0.5 + 0.1*0.5
+				Score:   0.624,
+				Reasons: []string{"Bought by people who bought Alpha", "Matches your interest in Action"},
+			},
+		},
+		{
+			name: "genre affinity decides without co-purchases",
+			purchases: purchases(map[string][]int{
+				"me": {3},
+				"u4": {4},
+				"u5": {5},
+			}),
+			eligible: everything,
+			wantIDs:  []int{5, 4, 1, 2},
+			wantTop: Recommendation{
+				Movie:   catalog[4],
+				Score:   0.25,
+				Reasons: []string{"Matches your interest in Comedy"},
+			},
+		},
+		{
+			name: "already bought and ineligible movies are left out",
+			purchases: purchases(map[string][]int{
+				"me": {1, 2},
+				"u1": {1, 2, 4},
+				"u2": {2, 3},
+			}),
+			eligible: func(m entities.Movie) bool { return m.ID != 5 },
+			wantIDs:  []int{4, 3},
+			wantTop: Recommendation{
+				Movie: catalog[3],
+				// 0.6*(cos(2,1,1)+cos(3,1,1))/2 + 0.1*1/3
+				Score:   0.419,
+				Reasons: []string{"Bought by people who bought Alpha"},
+			},
+		},
+		{
+			name: "cold start falls back to popularity",
+			purchases: purchases(map[string][]int{
+				"u1": {1, 4},
+				"u2": {1, 4},
+				"u3": {1, 3},
+			}),
+			eligible: everything,
+			wantIDs:  []int{1, 4, 3, 2, 5},
+			wantTop: Recommendation{
+				Movie:   catalog[0],
+				Score:   0.1,
+				Reasons: []string{"Popular with other moviegoers"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Rank("me", catalog, tt.purchases, tt.eligible)
+
+			ids := make([]int, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Fatalf("order = %v, want %v", ids, tt.wantIDs)
+			}
+			if !reflect.DeepEqual(got[0], tt.wantTop) {
+				t.Errorf("top = %+v, want %+v", got[0], tt.wantTop)
+			}
+		})
+	}
+}
+
+func TestRankIsDeterministic(t *testing.T) {
+	bought := purchases(map[string][]int{
+		"me": {1},
+		"u1": {1, 2, 3},
+		"u2": {1, 3, 2},
+		"u3": {4, 5},
+	})
+	reversed := make([]entities.Movie, len(catalog))
+	for i, m := range catalog {
+		reversed[len(catalog)-1-i] = m
+	}
+	shuffled := make([]Purchase, len(bought))
+	for i, p := range bought {
+		shuffled[len(bought)-1-i] = p
+	}
+
+	want := Rank("me", catalog, bought, everything)
+	for i := 0; i < 20; i++ {
+		if got := Rank("me", reversed, shuffled, everything); !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d = %+v, want %+v", i, got, want)
+		}
+	}
+	// Movies 4 and 5 tie on every signal, the lower ID comes first
+	last, prev := want[len(want)-1], want[len(want)-2]
+	if prev.ID != 4 || last.ID != 5 || prev.Score != last.Score {
+		t.Errorf("tie order = %d (%v), %d (%v), want 4 then 5 with equal scores", prev.ID, prev.Score, last.ID, last.Score)
+	}
+}
@@ -0,0 +1,10 @@
+package recommend
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+	UserRouter := r.Group("/user", middleware)
+	UserRouter.GET("/recommendations", h.GetRecommendations)
+}
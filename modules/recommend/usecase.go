@@ -0,0 +1,59 @@
+package recommend
+
+import (
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+	"time"
+)
+
+// Viewers decides which movies a user may be recommended
+type Viewers interface {
+	NotPermitted(m entities.Movie, u entities.User) bool
+}
+
+type useCase struct {
+	ticketRepo repositories.TicketRepositoryInterface
+	movieRepo  repositories.MovieRepositoryInterface
+	viewers    Viewers
+}
+
+type UseCaseInterface interface {
+	ForUser(u entities.User, limit int, now time.Time) ([]Recommendation, error)
+}
+
+func NewUseCase(ticketRepo repositories.TicketRepositoryInterface, movieRepo repositories.MovieRepositoryInterface, viewers Viewers) UseCaseInterface {
+	return &useCase{
+		ticketRepo: ticketRepo,
+		movieRepo:  movieRepo,
+		viewers:    viewers,
+	}
+}
+
+// ForUser recommends movies the user can still see and is old enough for,
+// ranked from every ticket sold so far
+func (usecase *useCase) ForUser(u entities.User, limit int, now time.Time) ([]Recommendation, error) {
+	tickets, err := usecase.ticketRepo.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	movies, err := usecase.movieRepo.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	purchases := make([]Purchase, 0, len(tickets))
+	for _, t := range tickets {
+		purchases = append(purchases, Purchase{UserID: t.UserID, MovieID: t.Movie.ID})
+		// A ticket handed on still tells what its first buyer wanted
+		for _, owner := range t.PreviousOwners {
+			purchases = append(purchases, Purchase{UserID: owner.UserID, MovieID: t.Movie.ID})
+		}
+	}
+	recommendations := Rank(u.ID, movies, purchases, func(m entities.Movie) bool {
+		return now.Before(m.Showtime) && !usecase.viewers.NotPermitted(m, u)
+	})
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
@@ -0,0 +1,20 @@
+package review
+
+type (
+	PostRequest struct {
+		Rating int    `json:"rating" binding:"required,min=1,max=5"`
+		Text   string `json:"text" binding:"max=2000"`
+	}
+	HideRequest struct {
+		Note string `json:"note" binding:"required,max=500"`
+	}
+	ListRequest struct {
+		Status string `form:"status" binding:"omitempty,oneof=published hidden"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
@@ -0,0 +1,227 @@
+package review
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Accounts resolves the caller of a user route
+type Accounts interface {
+	GetUser(username string) (entities.User, error)
+}
+
+type handler struct {
+	reviewUseCase UseCaseInterface
+	accounts      Accounts
+}
+
+type HandlerInterface interface {
+	GetMovieReviews(c *gin.Context)
+	PostReview(c *gin.Context)
+	DeleteReview(c *gin.Context)
+	GetReviews(c *gin.Context)
+	HideReview(c *gin.Context)
+	RestoreReview(c *gin.Context)
+	RemoveReview(c *gin.Context)
+}
+
+func NewHandler(reviewUseCase UseCaseInterface, accounts Accounts) HandlerInterface {
+	return &handler{
+		reviewUseCase: reviewUseCase,
+		accounts:      accounts,
+	}
+}
+
+func (h handler) GetMovieReviews(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetMovieReviews.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	reviews, err := h.reviewUseCase.GetForMovie(id)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.GetMovieReviews.02", "error", err)
+		h.failed(c, "FAILED_USECASE", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    reviews,
+	})
+}
+
+func (h handler) PostReview(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.PostReview.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	var req PostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.PostReview.02", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.PostReview.03")
+	if !ok {
+		return
+	}
+	review, err := h.reviewUseCase.Post(user, movieID, req.Rating, req.Text)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.PostReview.04", "error", err)
+		h.failed(c, "FAILED_POST_REVIEW", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "REVIEW_POSTED",
+		Data:    review,
+	})
+}
+
+func (h handler) DeleteReview(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.DeleteReview.01")
+	if !ok {
+		return
+	}
+	if err := h.reviewUseCase.Delete(user, c.Param("id")); err != nil {
+		logger.FromContext(c).Warn("Handler.DeleteReview.02", "error", err)
+		h.failed(c, "FAILED_DELETE_REVIEW", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "REVIEW_DELETED",
+		Data:    c.Param("id"),
+	})
+}
+
+func (h handler) GetReviews(c *gin.Context) {
+	var req ListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.FromContext(c).Error("Handler.GetReviews.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	reviews, err := h.reviewUseCase.GetAll(req.Status)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetReviews.02", "error", err)
+		h.failed(c, "FAILED_USECASE", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    reviews,
+	})
+}
+
+func (h handler) HideReview(c *gin.Context) {
+	var req HideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.HideReview.01", "error", err)
+		h.badRequest(c, err)
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	review, err := h.reviewUseCase.Hide(c.Param("id"), authInfo.(auth.AuthInfo).Username, req.Note)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.HideReview.02", "error", err)
+		h.failed(c, "FAILED_HIDE_REVIEW", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "REVIEW_HIDDEN",
+		Data:    review,
+	})
+}
+
+func (h handler) RestoreReview(c *gin.Context) {
+	authInfo, _ := c.Get("AuthInfo")
+	review, err := h.reviewUseCase.Restore(c.Param("id"), authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.RestoreReview.01", "error", err)
+		h.failed(c, "FAILED_RESTORE_REVIEW", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "REVIEW_RESTORED",
+		Data:    review,
+	})
+}
+
+func (h handler) RemoveReview(c *gin.Context) {
+	if err := h.reviewUseCase.Remove(c.Param("id")); err != nil {
+		logger.FromContext(c).Warn("Handler.RemoveReview.01", "error", err)
+		h.failed(c, "FAILED_REMOVE_REVIEW", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "REVIEW_REMOVED",
+		Data:    c.Param("id"),
+	})
+}
+
+func (h handler) currentUser(c *gin.Context, tag string) (entities.User, bool) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.accounts.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error(tag, "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return entities.User{}, false
+	}
+	return user, true
+}
+
+func (h handler) badRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, Response{
+		Code:      http.StatusBadRequest,
+		Message:   "BAD_REQUEST",
+		Data:      err.Error(),
+		RequestID: logger.RequestID(c),
+	})
+}
+
+func (h handler) failed(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrReviewNotFound), errors.Is(err, ErrMovieNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNotEligible):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrInvalidStatus):
+		status = http.StatusConflict
+	}
+	c.JSON(status, Response{
+		Code:      status,
+		Message:   message,
+		Data:      err.Error(),
+		RequestID: logger.RequestID(c),
+	})
+}
@@ -0,0 +1,19 @@
+package review
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc, adminOnly gin.HandlerFunc) {
+	r.GET("/movie/:id/reviews", h.GetMovieReviews)
+
+	UserRouter := r.Group("/user/reviews", middleware)
+	UserRouter.POST("/:movie_id", h.PostReview)
+	UserRouter.DELETE("/:id", h.DeleteReview)
+
+	AdminRouter := r.Group("/admin/reviews", middleware, adminOnly)
+	AdminRouter.GET("", h.GetReviews)
+	AdminRouter.POST("/:id/hide", h.HideReview)
+	AdminRouter.POST("/:id/restore", h.RestoreReview)
+	AdminRouter.DELETE("/:id", h.RemoveReview)
+}
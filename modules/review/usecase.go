@@ -0,0 +1,271 @@
+package review
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReviewNotFound = errors.New("REVIEW_NOT_FOUND")
+	ErrMovieNotFound  = errors.New("MOVIE_NOT_FOUND")
+	ErrNotEligible    = errors.New("NO_ATTENDED_TICKET")
+	ErrInvalidRating  = errors.New("INVALID_RATING")
+	ErrInvalidStatus  = errors.New("INVALID_REVIEW_STATUS")
+)
+
+const (
+	minRating = 1
+	maxRating = 5
+)
+
+// MovieReviews is the public view of a movie's reviews
+type MovieReviews struct {
+	Summary entities.RatingSummary `json:"summary"`
+	Reviews []entities.Review      `json:"reviews"`
+}
+
+type useCase struct {
+	// mu keeps one review per user and movie when posts race
+	mu         sync.Mutex
+	reviewRepo repositories.ReviewRepositoryInterface
+	ticketRepo repositories.TicketRepositoryInterface
+	movieRepo  repositories.MovieRepositoryInterface
+}
+
+type UseCaseInterface interface {
+	Post(u entities.User, movieID, rating int, text string) (entities.Review, error)
+	Delete(u entities.User, id string) error
+	GetForMovie(movieID int) (MovieReviews, error)
+	GetAll(status string) ([]entities.Review, error)
+	Hide(id, admin, note string) (entities.Review, error)
+	Restore(id, admin string) (entities.Review, error)
+	Remove(id string) error
+	Summary(movieID int) entities.RatingSummary
+	Summaries() map[int]entities.RatingSummary
+	Version() (uint64, time.Time)
+}
+
+func NewUseCase(reviewRepo repositories.ReviewRepositoryInterface, ticketRepo repositories.TicketRepositoryInterface, movieRepo repositories.MovieRepositoryInterface) UseCaseInterface {
+	return &useCase{
+		reviewRepo: reviewRepo,
+		ticketRepo: ticketRepo,
+		movieRepo:  movieRepo,
+	}
+}
+
+// Post creates the user's review of a movie, or replaces the rating and
+// text of the one they already wrote. Only users holding a ticket that was
+// checked in or whose showtime has passed may review
+func (usecase *useCase) Post(u entities.User, movieID, rating int, text string) (entities.Review, error) {
+	if rating < minRating || rating > maxRating {
+		return entities.Review{}, ErrInvalidRating
+	}
+	if _, err := usecase.movieRepo.Read(movieID); err != nil {
+		return entities.Review{}, ErrMovieNotFound
+	}
+	now := time.Now()
+	attended, err := usecase.attended(u, movieID, now)
+	if err != nil {
+		return entities.Review{}, err
+	}
+	if !attended {
+		return entities.Review{}, ErrNotEligible
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	reviews, err := usecase.reviewRepo.ReadByMovie(movieID)
+	if err != nil {
+		return entities.Review{}, err
+	}
+	for _, review := range reviews {
+		if review.UserID != u.ID {
+			continue
+		}
+		// A hidden review stays hidden when edited, moderators restore it
+		review.Rating = rating
+		review.Text = text
+		review.Updated_at = now
+		if err := usecase.reviewRepo.Update(review); err != nil {
+			return entities.Review{}, err
+		}
+		return review, nil
+	}
+
+	review := entities.Review{
+		ID:         uuid.NewString(),
+		MovieID:    movieID,
+		UserID:     u.ID,
+		Username:   u.Username,
+		Rating:     rating,
+		Text:       text,
+		Status:     entities.ReviewPublished,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if err := usecase.reviewRepo.Create(review); err != nil {
+		return entities.Review{}, err
+	}
+	return review, nil
+}
+
+func (usecase *useCase) Delete(u entities.User, id string) error {
+	review, err := usecase.reviewRepo.Read(id)
+	if err != nil || review.UserID != u.ID {
+		return ErrReviewNotFound
+	}
+	return usecase.reviewRepo.Delete(id)
+}
+
+// GetForMovie lists the published reviews of a movie, newest first
+func (usecase *useCase) GetForMovie(movieID int) (MovieReviews, error) {
+	if _, err := usecase.movieRepo.Read(movieID); err != nil {
+		return MovieReviews{}, ErrMovieNotFound
+	}
+	reviews, err := usecase.reviewRepo.ReadByMovie(movieID)
+	if err != nil {
+		return MovieReviews{}, err
+	}
+	published := make([]entities.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Status == entities.ReviewPublished {
+			published = append(published, review)
+		}
+	}
+	sort.SliceStable(published, func(a, b int) bool {
+		return published[a].Created_at.After(published[b].Created_at)
+	})
+	return MovieReviews{
+		Summary: summarize(published),
+		Reviews: published,
+	}, nil
+}
+
+// GetAll lists every review for moderation, filtered by status when given
+func (usecase *useCase) GetAll(status string) ([]entities.Review, error) {
+	reviews, err := usecase.reviewRepo.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]entities.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if status == "" || review.Status == status {
+			result = append(result, review)
+		}
+	}
+	return result, nil
+}
+
+func (usecase *useCase) Hide(id, admin, note string) (entities.Review, error) {
+	return usecase.moderate(id, admin, note, entities.ReviewPublished, entities.ReviewHidden)
+}
+
+func (usecase *useCase) Restore(id, admin string) (entities.Review, error) {
+	return usecase.moderate(id, admin, "", entities.ReviewHidden, entities.ReviewPublished)
+}
+
+func (usecase *useCase) Remove(id string) error {
+	if err := usecase.reviewRepo.Delete(id); err != nil {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+func (usecase *useCase) Summary(movieID int) entities.RatingSummary {
+	reviews, _ := usecase.reviewRepo.ReadByMovie(movieID)
+	var published []entities.Review
+	for _, review := range reviews {
+		if review.Status == entities.ReviewPublished {
+			published = append(published, review)
+		}
+	}
+	return summarize(published)
+}
+
+// Summaries aggregates the published reviews of every reviewed movie
+func (usecase *useCase) Summaries() map[int]entities.RatingSummary {
+	reviews, _ := usecase.reviewRepo.ReadAll()
+	byMovie := make(map[int][]entities.Review)
+	for _, review := range reviews {
+		if review.Status == entities.ReviewPublished {
+			byMovie[review.MovieID] = append(byMovie[review.MovieID], review)
+		}
+	}
+	summaries := make(map[int]entities.RatingSummary, len(byMovie))
+	for movieID, published := range byMovie {
+		summaries[movieID] = summarize(published)
+	}
+	return summaries
+}
+
+// Version changes with every review written, moderated or removed
+func (usecase *useCase) Version() (uint64, time.Time) {
+	return usecase.reviewRepo.Version()
+}
+
+func (usecase *useCase) moderate(id, admin, note, from, to string) (entities.Review, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	review, err := usecase.reviewRepo.Read(id)
+	if err != nil {
+		return entities.Review{}, ErrReviewNotFound
+	}
+	if review.Status != from {
+		return entities.Review{}, ErrInvalidStatus
+	}
+	now := time.Now()
+	review.Status = to
+	review.Moderated_by = admin
+	review.Moderation_note = note
+	review.Moderated_at = now
+	review.Updated_at = now
+	if err := usecase.reviewRepo.Update(review); err != nil {
+		return entities.Review{}, err
+	}
+	return review, nil
+}
+
+// attended tells whether the user holds a ticket for the movie that was
+// checked in or whose showtime has passed. Cancelled tickets are deleted
+// and never count
+func (usecase *useCase) attended(u entities.User, movieID int, now time.Time) (bool, error) {
+	tickets, err := usecase.ticketRepo.ReadByUser(u.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, ticket := range tickets {
+		if ticket.Movie.ID != movieID {
+			continue
+		}
+		if ticket.CheckedIn() || !ticket.Movie.Showtime.After(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// summarize averages ratings to one decimal place
+func summarize(reviews []entities.Review) entities.RatingSummary {
+	if len(reviews) == 0 {
+		return entities.RatingSummary{}
+	}
+	total := 0
+	for _, review := range reviews {
+		total += review.Rating
+	}
+	average := float64(total) / float64(len(reviews))
+	return entities.RatingSummary{
+		Average: math.Round(average*10) / 10,
+		Count:   len(reviews),
+	}
+}
@@ -0,0 +1,245 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"movie-app-go/entities"
+)
+
+// Field weights, a word in the title counts for more than one in the
+// description
+const (
+	titleWeight       = 3.0
+	castWeight        = 2.0
+	descriptionWeight = 1.0
+)
+
+// Match quality multipliers for query words that are not found as typed
+const (
+	prefixMatch = 0.7
+	typoMatch   = 0.5
+	typosMatch  = 0.3
+)
+
+// Hit is a movie matching a query, higher scores are more relevant
+type Hit struct {
+	MovieID int     `json:"movie_id"`
+	Title   string  `json:"title"`
+	Score   float64 `json:"score"`
+}
+
+// Options tune how query words match indexed words
+type Options struct {
+	// Prefix lets the last query word match the start of a word, for
+	// queries typed as the user goes
+	Prefix bool
+	// Fuzzy tolerates one typo in words of four letters or more and two in
+	// words of eight or more
+	Fuzzy bool
+	Limit int
+}
+
+type index struct {
+	mu sync.RWMutex
+	// postings maps a word to the weighted count of it in each movie
+	postings map[string]map[int]float64
+	// words remembers what each movie was indexed under, for updates
+	words  map[int][]string
+	titles map[int]string
+	// vocabulary is the sorted set of indexed words for prefix and fuzzy
+	// lookups
+	vocabulary []string
+}
+
+type IndexInterface interface {
+	Put(m entities.Movie)
+	Remove(id int)
+	Search(query string, opts Options) []Hit
+}
+
+// NewIndex returns an empty in-memory inverted index over movie titles,
+// descriptions and cast
+func NewIndex() IndexInterface {
+	return &index{
+		postings: make(map[string]map[int]float64),
+		words:    make(map[int][]string),
+		titles:   make(map[int]string),
+	}
+}
+
+// Put indexes a movie, replacing what was indexed for it before
+func (ix *index) Put(m entities.Movie) {
+	weights := make(map[string]float64)
+	for _, word := range Tokenize(m.Title) {
+		weights[word] += titleWeight
+	}
+	for _, word := range Tokenize(strings.Join(m.Cast, " ")) {
+		weights[word] += castWeight
+	}
+	for _, word := range Tokenize(m.Description) {
+		weights[word] += descriptionWeight
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	changed := ix.remove(m.ID)
+	words := make([]string, 0, len(weights))
+	for word, weight := range weights {
+		movies, ok := ix.postings[word]
+		if !ok {
+			movies = make(map[int]float64)
+			ix.postings[word] = movies
+			changed = true
+		}
+		// Repeats add less than the first occurrence
+		movies[m.ID] = 1 + math.Log(weight)
+		words = append(words, word)
+	}
+	ix.words[m.ID] = words
+	ix.titles[m.ID] = m.Title
+	if changed {
+		ix.sortVocabulary()
+	}
+}
+
+func (ix *index) Remove(id int) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if ix.remove(id) {
+		ix.sortVocabulary()
+	}
+}
+
+// Search ranks movies by how well they match every word of the query. Each
+// query word scores its best match in a movie, scaled by how rare the
+// matched word is across the catalog, and movies matching only some of the
+// words are ranked below those matching all of them
+func (ix *index) Search(query string, opts Options) []Hit {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return []Hit{}
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	total := float64(len(ix.titles))
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for i, term := range terms {
+		prefix := opts.Prefix && i == len(terms)-1
+		best := make(map[int]float64)
+		for word, quality := range ix.expand(term, prefix, opts.Fuzzy) {
+			movies := ix.postings[word]
+			idf := math.Log(1 + total/float64(len(movies)))
+			for id, weight := range movies {
+				if score := quality * idf * weight; score > best[id] {
+					best[id] = score
+				}
+			}
+		}
+		for id, score := range best {
+			scores[id] += score
+			matched[id]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		coverage := float64(matched[id]) / float64(len(terms))
+		hits = append(hits, Hit{
+			MovieID: id,
+			Title:   ix.titles[id],
+			Score:   math.Round(score*coverage*1000) / 1000,
+		})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].MovieID < hits[b].MovieID
+	})
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	return hits
+}
+
+// expand finds the indexed words a query word stands for, with the
+// quality of each match
+func (ix *index) expand(term string, prefix, fuzzy bool) map[string]float64 {
+	words := make(map[string]float64)
+	if _, ok := ix.postings[term]; ok {
+		words[term] = 1
+	}
+	if prefix {
+		start := sort.SearchStrings(ix.vocabulary, term)
+		for _, word := range ix.vocabulary[start:] {
+			if !strings.HasPrefix(word, term) {
+				break
+			}
+			if _, ok := words[word]; !ok {
+				words[word] = prefixMatch
+			}
+		}
+	}
+	if !fuzzy {
+		return words
+	}
+	runes := []rune(term)
+	maxTypos := 0
+	switch {
+	case len(runes) >= 8:
+		maxTypos = 2
+	case len(runes) >= 4:
+		maxTypos = 1
+	}
+	if maxTypos == 0 {
+		return words
+	}
+	for _, word := range ix.vocabulary {
+		if _, ok := words[word]; ok {
+			continue
+		}
+		typos := distance(runes, []rune(word), maxTypos)
+		if typos > maxTypos {
+			continue
+		}
+		switch typos {
+		case 1:
+			words[word] = typoMatch
+		case 2:
+			words[word] = typosMatch
+		}
+	}
+	return words
+}
+
+// remove drops a movie from the postings, reporting whether any word left
+// the vocabulary
+func (ix *index) remove(id int) bool {
+	changed := false
+	for _, word := range ix.words[id] {
+		delete(ix.postings[word], id)
+		if len(ix.postings[word]) == 0 {
+			delete(ix.postings, word)
+			changed = true
+		}
+	}
+	delete(ix.words, id)
+	delete(ix.titles, id)
+	return changed
+}
+
+func (ix *index) sortVocabulary() {
+	ix.vocabulary = ix.vocabulary[:0]
+	for word := range ix.postings {
+		ix.vocabulary = append(ix.vocabulary, word)
+	}
+	sort.Strings(ix.vocabulary)
+}
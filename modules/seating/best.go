@@ -0,0 +1,181 @@
+package seating
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"movie-app-go/entities"
+)
+
+var (
+	ErrInvalidPartySize = errors.New("INVALID_PARTY_SIZE")
+	ErrPartyTooLarge    = errors.New("MAX_TICKET_REACH")
+	ErrInvalidRow       = errors.New("INVALID_ROW")
+	ErrNoSeats          = errors.New("TICKET_UNAVAILABLE")
+	ErrNoContiguous     = errors.New("NO_CONTIGUOUS_BLOCK")
+)
+
+// Options tune how a block of seats is picked
+type Options struct {
+	PartySize int
+	// PreferredRow is the row the party would ideally sit in, empty picks
+	// a row two thirds of the way back from the screen
+	PreferredRow string
+	// ContiguousOnly disables the split seating fallback
+	ContiguousOnly bool
+	// MaxPartySize rejects larger parties, zero means no limit
+	MaxPartySize int
+}
+
+type Suggestion struct {
+	Seats      []entities.Seat `json:"seats"`
+	Contiguous bool            `json:"contiguous"`
+	Score      float64         `json:"score"`
+}
+
+type block struct {
+	row   int
+	seats []entities.Seat
+	score float64
+}
+
+// Suggest returns the best seats for a party. Rows are ordered as they appear
+// in the inventory, the first row being closest to the screen. A block scores
+// higher the closer it is to the centre column and to the preferred row. When
+// no single row has room for the whole party the party is split into as few
+// blocks as possible
+func Suggest(seats []entities.Seat, opts Options, free func(entities.Seat) bool) (Suggestion, error) {
+	if opts.PartySize < 1 {
+		return Suggestion{}, ErrInvalidPartySize
+	}
+	if opts.MaxPartySize > 0 && opts.PartySize > opts.MaxPartySize {
+		return Suggestion{}, ErrPartyTooLarge
+	}
+
+	rows, columns := grid(seats, free)
+	preferred := (2*len(rows) - 2) / 3
+	if opts.PreferredRow != "" {
+		preferred = -1
+		for i, row := range rows {
+			if row.name == opts.PreferredRow {
+				preferred = i
+			}
+		}
+		if preferred < 0 {
+			return Suggestion{}, ErrInvalidRow
+		}
+	}
+
+	if best, ok := bestBlock(rows, opts.PartySize, columns, preferred); ok {
+		return Suggestion{Seats: best.seats, Contiguous: true, Score: best.score}, nil
+	}
+	if opts.ContiguousOnly {
+		return Suggestion{}, ErrNoContiguous
+	}
+
+	// Split seating, repeatedly take the best of the largest blocks left
+	suggestion := Suggestion{}
+	remaining := opts.PartySize
+	for remaining > 0 {
+		found := false
+		for size := remaining; size >= 1; size-- {
+			if best, ok := bestBlock(rows, size, columns, preferred); ok {
+				suggestion.Seats = append(suggestion.Seats, best.seats...)
+				suggestion.Score += best.score * float64(size)
+				rows[best.row].take(best.seats)
+				remaining -= size
+				found = true
+				break
+			}
+		}
+		if !found {
+			return Suggestion{}, ErrNoSeats
+		}
+	}
+	suggestion.Score /= float64(opts.PartySize)
+
+	return suggestion, nil
+}
+
+type row struct {
+	name  string
+	seats []entities.Seat
+	free  map[int]bool
+}
+
+func (r *row) take(seats []entities.Seat) {
+	for _, seat := range seats {
+		r.free[seat.Number] = false
+	}
+}
+
+func grid(seats []entities.Seat, free func(entities.Seat) bool) ([]*row, int) {
+	var rows []*row
+	index := make(map[string]*row)
+	columns := 0
+
+	for _, seat := range seats {
+		r, ok := index[seat.Row]
+		if !ok {
+			r = &row{name: seat.Row, free: make(map[int]bool)}
+			index[seat.Row] = r
+			rows = append(rows, r)
+		}
+		r.seats = append(r.seats, seat)
+		r.free[seat.Number] = free(seat)
+		if seat.Number > columns {
+			columns = seat.Number
+		}
+	}
+	for _, r := range rows {
+		sort.Slice(r.seats, func(a, b int) bool {
+			return r.seats[a].Number < r.seats[b].Number
+		})
+	}
+
+	return rows, columns
+}
+
+// bestBlock scans every window of size consecutive free seats. Ties go to
+// the row closer to the screen, then to the lower seat numbers
+func bestBlock(rows []*row, size, columns, preferred int) (block, bool) {
+	var (
+		best  block
+		found bool
+	)
+	centre := float64(columns+1) / 2
+	rowSpan := math.Max(1, float64(len(rows)-1))
+
+	for ri, r := range rows {
+		for start := 0; start+size <= len(r.seats); start++ {
+			window := r.seats[start : start+size]
+			if !consecutiveFree(r, window) {
+				continue
+			}
+			mid := float64(window[0].Number+window[size-1].Number) / 2
+			centreScore := 1 - math.Abs(mid-centre)/centre
+			rowScore := 1 - math.Abs(float64(ri-preferred))/rowSpan
+			score := (centreScore + rowScore) / 2
+
+			if !found || score > best.score+1e-9 {
+				best = block{row: ri, seats: append([]entities.Seat(nil), window...), score: score}
+				found = true
+			}
+		}
+	}
+
+	return best, found
+}
+
+func consecutiveFree(r *row, window []entities.Seat) bool {
+	for i, seat := range window {
+		if !r.free[seat.Number] {
+			return false
+		}
+		if i > 0 && seat.Number != window[i-1].Number+1 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package seating
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"movie-app-go/entities"
+)
+
+// hall has rows A to E of nine seats, A closest to the screen
+func hall() []entities.Seat {
+	var seats []entities.Seat
+	for _, row := range []string{"A", "B", "C", "D", "E"} {
+		for number := 1; number <= 9; number++ {
+			seats = append(seats, entities.Seat{Row: row, Number: number})
+		}
+	}
+	return seats
+}
+
+// freeExcept treats every seat as free but the listed ones
+func freeExcept(taken ...string) func(entities.Seat) bool {
+	return func(s entities.Seat) bool {
+		return !slices.Contains(taken, label(s))
+	}
+}
+
+// freeOnly treats only the listed seats as free
+func freeOnly(free ...string) func(entities.Seat) bool {
+	return func(s entities.Seat) bool {
+		return slices.Contains(free, label(s))
+	}
+}
+
+func label(s entities.Seat) string {
+	return fmt.Sprintf("%s%d", s.Row, s.Number)
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           Options
+		free           func(entities.Seat) bool
+		want           []string
+		wantContiguous bool
+		wantErr        error
+	}{
+		{
+			name:           "single seat in the default row",
+			opts:           Options{PartySize: 1},
+			free:           freeExcept(),
+			want:           []string{"C5"},
+			wantContiguous: true,
+		},
+		{
+			name:           "pair in the preferred row",
+			opts:           Options{PartySize: 2, PreferredRow: "B"},
+			free:           freeExcept(),
+			want:           []string{"B4", "B5"},
+			wantContiguous: true,
+		},
+		{
+			// B and D score the same, the row closer to the screen wins
+			name:           "centre taken moves the party a row",
+			opts:           Options{PartySize: 3, PreferredRow: "C"},
+			free:           freeExcept("C4", "C5", "C6"),
+			want:           []string{"B4", "B5", "B6"},
+			wantContiguous: true,
+		},
+		{
+			name:           "party split when no row has room",
+			opts:           Options{PartySize: 3},
+			free:           freeOnly("A1", "A2", "E8", "E9"),
+			want:           []string{"A1", "A2", "E8"},
+			wantContiguous: false,
+		},
+		{
+			name:    "contiguous only refuses to split",
+			opts:    Options{PartySize: 3, ContiguousOnly: true},
+			free:    freeOnly("A1", "A2", "E8", "E9"),
+			wantErr: ErrNoContiguous,
+		},
+		{
+			name:    "not enough seats",
+			opts:    Options{PartySize: 3},
+			free:    freeOnly("A1", "E9"),
+			wantErr: ErrNoSeats,
+		},
+		{
+			name:    "empty party",
+			opts:    Options{PartySize: 0},
+			free:    freeExcept(),
+			wantErr: ErrInvalidPartySize,
+		},
+		{
+			name:    "party above the limit",
+			opts:    Options{PartySize: 5, MaxPartySize: 4},
+			free:    freeExcept(),
+			wantErr: ErrPartyTooLarge,
+		},
+		{
+			name:    "unknown row",
+			opts:    Options{PartySize: 2, PreferredRow: "Z"},
+			free:    freeExcept(),
+			wantErr: ErrInvalidRow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Suggest(hall(), tt.opts, tt.free)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			labels := make([]string, len(got.Seats))
+			for i, seat := range got.Seats {
+				labels[i] = label(seat)
+			}
+			if !slices.Equal(labels, tt.want) || got.Contiguous != tt.wantContiguous {
+				t.Errorf("Suggest = %v contiguous %v, want %v contiguous %v", labels, got.Contiguous, tt.want, tt.wantContiguous)
+			}
+		})
+	}
+}
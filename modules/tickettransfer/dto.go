@@ -0,0 +1,13 @@
+package tickettransfer
+
+type (
+	OfferRequest struct {
+		To_username string `json:"to_username" binding:"required"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
@@ -0,0 +1,185 @@
+package tickettransfer
+
+import (
+	"errors"
+	"net/http"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	transferUseCase UseCaseInterface
+	tickets         Tickets
+}
+
+type HandlerInterface interface {
+	OfferTicket(c *gin.Context)
+	GetOffers(c *gin.Context)
+	AcceptOffer(c *gin.Context)
+	DeclineOffer(c *gin.Context)
+	CancelOffer(c *gin.Context)
+}
+
+func NewHandler(transferUseCase UseCaseInterface, tickets Tickets) HandlerInterface {
+	return &handler{
+		transferUseCase: transferUseCase,
+		tickets:         tickets,
+	}
+}
+
+func (h handler) OfferTicket(c *gin.Context) {
+	var req OfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.OfferTicket.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.OfferTicket.02")
+	if !ok {
+		return
+	}
+	offer, err := h.transferUseCase.Offer(user, c.Param("id"), req.To_username)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.OfferTicket.03", "error", err)
+
+		status := errorStatus(err)
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_OFFER",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Code:    http.StatusCreated,
+		Message: "SUCCESS_OFFER",
+		Data:    offer,
+	})
+}
+
+func (h handler) GetOffers(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.GetOffers.01")
+	if !ok {
+		return
+	}
+	offers, err := h.transferUseCase.GetOffers(user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetOffers.02", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    offers,
+	})
+}
+
+func (h handler) AcceptOffer(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.AcceptOffer.01")
+	if !ok {
+		return
+	}
+	ticket, err := h.transferUseCase.Accept(user, c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.AcceptOffer.02", "error", err)
+
+		status := errorStatus(err)
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_ACCEPT",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS_ACCEPT",
+		Data:    ticket,
+	})
+}
+
+func (h handler) DeclineOffer(c *gin.Context) {
+	h.closeOffer(c, "Handler.DeclineOffer", h.transferUseCase.Decline, "DECLINE")
+}
+
+func (h handler) CancelOffer(c *gin.Context) {
+	h.closeOffer(c, "Handler.CancelOffer", h.transferUseCase.Cancel, "CANCEL")
+}
+
+func (h handler) closeOffer(c *gin.Context, tag string, respond func(entities.User, string) (entities.TicketOffer, error), action string) {
+	user, ok := h.currentUser(c, tag+".01")
+	if !ok {
+		return
+	}
+	offer, err := respond(user, c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Warn(tag+".02", "error", err)
+
+		status := errorStatus(err)
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_" + action,
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS_" + action,
+		Data:    offer,
+	})
+}
+
+func (h handler) currentUser(c *gin.Context, tag string) (entities.User, bool) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.tickets.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error(tag, "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return entities.User{}, false
+	}
+	return user, true
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotOwned), errors.Is(err, ErrOfferNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotPermitted):
+		return http.StatusForbidden
+	case errors.Is(err, ErrOfferPending), errors.Is(err, ErrOfferNotPending),
+		errors.Is(err, ErrOfferExpired), errors.Is(err, ErrShowStarted), errors.Is(err, ErrTicketUsed):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,14 @@
+package tickettransfer
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+	UserRouter := r.Group("/user", middleware)
+	UserRouter.POST("/tickets/:id/transfer", h.OfferTicket)
+	UserRouter.GET("/ticket-offers", h.GetOffers)
+	UserRouter.POST("/ticket-offers/:id/accept", h.AcceptOffer)
+	UserRouter.POST("/ticket-offers/:id/decline", h.DeclineOffer)
+	UserRouter.POST("/ticket-offers/:id/cancel", h.CancelOffer)
+}
@@ -0,0 +1,247 @@
+package tickettransfer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+// An offer lapses after offerLifetime or at the showtime, whichever is first
+const offerLifetime = 48 * time.Hour
+
+var (
+	ErrSelfTransfer    = errors.New("SELF_TRANSFER")
+	ErrNotOwned        = errors.New("TICKET_NOT_OWNED")
+	ErrOfferPending    = errors.New("OFFER_ALREADY_PENDING")
+	ErrOfferNotFound   = errors.New("OFFER_NOT_FOUND")
+	ErrOfferNotPending = errors.New("OFFER_NOT_PENDING")
+	ErrOfferExpired    = errors.New("OFFER_EXPIRED")
+	ErrShowStarted     = errors.New("SHOW_ALREADY_STARTED")
+	ErrNotPermitted    = errors.New("NOT_PERMITTED")
+	ErrTicketUsed      = errors.New("TICKET_ALREADY_USED")
+)
+
+// Tickets is the part of the user use case ticket transfers work through
+type Tickets interface {
+	GetUser(username string) (entities.User, error)
+	GetMovie(id int) (entities.Movie, error)
+	GetTicket(id string) (entities.Ticket, error)
+	NotPermitted(m entities.Movie, u entities.User) bool
+	MoveTicket(ticketID, from, to string) (entities.Ticket, error)
+}
+
+type useCase struct {
+	// mu keeps a ticket to one pending offer and one answer per offer
+	mu        sync.Mutex
+	offerRepo repositories.TicketOfferRepositoryInterface
+	tickets   Tickets
+}
+
+type UseCaseInterface interface {
+	Offer(from entities.User, ticketID, toUsername string) (entities.TicketOffer, error)
+	Accept(u entities.User, id string) (entities.Ticket, error)
+	Decline(u entities.User, id string) (entities.TicketOffer, error)
+	Cancel(u entities.User, id string) (entities.TicketOffer, error)
+	GetOffers(u entities.User) ([]entities.TicketOffer, error)
+}
+
+func NewUseCase(offerRepo repositories.TicketOfferRepositoryInterface, tickets Tickets) UseCaseInterface {
+	return &useCase{
+		offerRepo: offerRepo,
+		tickets:   tickets,
+	}
+}
+
+// Offer asks another user to take over a ticket. The recipient must be old
+// enough for the movie, using the same rule as buying a ticket
+func (usecase *useCase) Offer(from entities.User, ticketID, toUsername string) (entities.TicketOffer, error) {
+	if toUsername == from.Username {
+		return entities.TicketOffer{}, ErrSelfTransfer
+	}
+	ticket, err := usecase.tickets.GetTicket(ticketID)
+	if err != nil || ticket.UserID != from.ID {
+		return entities.TicketOffer{}, ErrNotOwned
+	}
+	if ticket.CheckedIn() {
+		return entities.TicketOffer{}, ErrTicketUsed
+	}
+	to, err := usecase.tickets.GetUser(toUsername)
+	if err != nil {
+		return entities.TicketOffer{}, err
+	}
+	now := time.Now()
+	movie, err := usecase.checkRecipient(ticket, to, now)
+	if err != nil {
+		return entities.TicketOffer{}, err
+	}
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		return entities.TicketOffer{}, err
+	}
+
+	expiresAt := now.Add(offerLifetime)
+	if movie.Showtime.Before(expiresAt) {
+		expiresAt = movie.Showtime
+	}
+	seats := make([]string, 0, len(ticket.Seats))
+	for _, seat := range ticket.Seats {
+		seats = append(seats, fmt.Sprintf("%s%d", seat.Row, seat.Number))
+	}
+	offer := entities.TicketOffer{
+		ID:            UUID.String(),
+		TicketID:      ticket.ID,
+		MovieID:       movie.ID,
+		MovieTitle:    movie.Title,
+		Seats:         seats,
+		From_user_id:  from.ID,
+		From_username: from.Username,
+		To_user_id:    to.ID,
+		To_username:   to.Username,
+		Status:        entities.OfferPending,
+		Expires_at:    expiresAt,
+		Created_at:    now,
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	offers, err := usecase.offerRepo.ReadByTicket(ticket.ID)
+	if err != nil {
+		return entities.TicketOffer{}, err
+	}
+	for _, existing := range offers {
+		if existing.Status == entities.OfferPending && now.Before(existing.Expires_at) {
+			return entities.TicketOffer{}, ErrOfferPending
+		}
+	}
+	if err := usecase.offerRepo.Create(offer); err != nil {
+		return entities.TicketOffer{}, err
+	}
+	return offer, nil
+}
+
+// Accept moves the ticket to the recipient. Ownership and age are checked
+// again, the sender may have cancelled the ticket since offering it
+func (usecase *useCase) Accept(u entities.User, id string) (entities.Ticket, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	offer, err := usecase.pending(id, u.ID, true)
+	if err != nil {
+		return entities.Ticket{}, err
+	}
+	ticket, err := usecase.tickets.GetTicket(offer.TicketID)
+	if err != nil || ticket.UserID != offer.From_user_id {
+		usecase.close(offer, entities.OfferCancelled)
+		return entities.Ticket{}, ErrNotOwned
+	}
+	if ticket.CheckedIn() {
+		usecase.close(offer, entities.OfferCancelled)
+		return entities.Ticket{}, ErrTicketUsed
+	}
+	if _, err := usecase.checkRecipient(ticket, u, time.Now()); err != nil {
+		return entities.Ticket{}, err
+	}
+
+	ticket, err = usecase.tickets.MoveTicket(ticket.ID, offer.From_username, u.Username)
+	if err != nil {
+		return entities.Ticket{}, err
+	}
+	usecase.close(offer, entities.OfferAccepted)
+	return ticket, nil
+}
+
+func (usecase *useCase) Decline(u entities.User, id string) (entities.TicketOffer, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	offer, err := usecase.pending(id, u.ID, true)
+	if err != nil {
+		return entities.TicketOffer{}, err
+	}
+	return usecase.close(offer, entities.OfferDeclined)
+}
+
+func (usecase *useCase) Cancel(u entities.User, id string) (entities.TicketOffer, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	offer, err := usecase.pending(id, u.ID, false)
+	if err != nil {
+		return entities.TicketOffer{}, err
+	}
+	return usecase.close(offer, entities.OfferCancelled)
+}
+
+// GetOffers lists the offers the user sent or received, newest first
+func (usecase *useCase) GetOffers(u entities.User) ([]entities.TicketOffer, error) {
+	offers, err := usecase.offerRepo.ReadByUser(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range offers {
+		if offers[i].Status == entities.OfferPending && !now.Before(offers[i].Expires_at) {
+			offers[i].Status = entities.OfferExpired
+		}
+	}
+	sort.SliceStable(offers, func(a, b int) bool {
+		return offers[a].Created_at.After(offers[b].Created_at)
+	})
+	return offers, nil
+}
+
+// pending finds a pending offer addressed to the user, or sent by them when
+// received is false
+func (usecase *useCase) pending(id, userID string, received bool) (entities.TicketOffer, error) {
+	offer, err := usecase.offerRepo.Read(id)
+	if err != nil {
+		return entities.TicketOffer{}, ErrOfferNotFound
+	}
+	if (received && offer.To_user_id != userID) || (!received && offer.From_user_id != userID) {
+		return entities.TicketOffer{}, ErrOfferNotFound
+	}
+	if offer.Status != entities.OfferPending {
+		return entities.TicketOffer{}, ErrOfferNotPending
+	}
+	if !time.Now().Before(offer.Expires_at) {
+		usecase.close(offer, entities.OfferExpired)
+		return entities.TicketOffer{}, ErrOfferExpired
+	}
+	return offer, nil
+}
+
+func (usecase *useCase) close(offer entities.TicketOffer, status string) (entities.TicketOffer, error) {
+	offer.Status = status
+	offer.Responded_at = time.Now()
+	if err := usecase.offerRepo.Update(offer); err != nil {
+		return entities.TicketOffer{}, err
+	}
+	return offer, nil
+}
+
+// checkRecipient checks that the ticket's screening is still ahead and the
+// recipient is old enough for it
+func (usecase *useCase) checkRecipient(ticket entities.Ticket, to entities.User, now time.Time) (entities.Movie, error) {
+	movie, err := usecase.tickets.GetMovie(ticket.Movie.ID)
+	if err != nil {
+		return entities.Movie{}, err
+	}
+	// The movie may have moved on to a later screening, the ticket is only
+	// good for the one it was bought for
+	movie.Showtime = ticket.Movie.Showtime
+	if !now.Before(movie.Showtime) {
+		return entities.Movie{}, ErrShowStarted
+	}
+	if usecase.tickets.NotPermitted(movie, to) {
+		return entities.Movie{}, ErrNotPermitted
+	}
+	return movie, nil
+}
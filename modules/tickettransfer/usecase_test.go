@@ -0,0 +1,88 @@
+package tickettransfer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+)
+
+// fakeTickets serves one movie and one ticket owned by alice
+type fakeTickets struct {
+	movie  entities.Movie
+	ticket entities.Ticket
+	moved  bool
+}
+
+func (f *fakeTickets) GetUser(username string) (entities.User, error) {
+	return entities.User{ID: "u-" + username, Username: username}, nil
+}
+
+func (f *fakeTickets) GetMovie(id int) (entities.Movie, error) { return f.movie, nil }
+
+func (f *fakeTickets) GetTicket(id string) (entities.Ticket, error) {
+	if id != f.ticket.ID {
+		return entities.Ticket{}, errors.New("NOT_FOUND")
+	}
+	return f.ticket, nil
+}
+
+func (f *fakeTickets) NotPermitted(m entities.Movie, u entities.User) bool { return false }
+
+func (f *fakeTickets) MoveTicket(ticketID, from, to string) (entities.Ticket, error) {
+	f.moved = true
+	f.ticket.UserID = "u-" + to
+	return f.ticket, nil
+}
+
+func TestTransferFollowsTheTicketsScreening(t *testing.T) {
+	tests := []struct {
+		name string
+		// bought and current are the ticket's showtime and the movie's
+		// current one, relative to now
+		bought, current time.Duration
+		// lapse moves the ticket's showtime to the past between offering
+		// and accepting
+		lapse      bool
+		wantOffer  error
+		wantAccept error
+	}{
+		{name: "upcoming screening", bought: time.Hour, current: time.Hour},
+		{name: "screening rolled over", bought: -time.Hour, current: 23 * time.Hour, wantOffer: ErrShowStarted},
+		{name: "screening started before accepting", bought: time.Hour, current: time.Hour, lapse: true, wantAccept: ErrShowStarted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			tickets := &fakeTickets{
+				movie:  entities.Movie{ID: 1, Title: "Fixture", Showtime: now.Add(tt.current)},
+				ticket: entities.Ticket{ID: "t-1", UserID: "u-alice", Movie: entities.Movie{ID: 1, Showtime: now.Add(tt.bought)}},
+			}
+			usecase := NewUseCase(repositories.NewTicketOfferRepository(nil), tickets)
+
+			offer, err := usecase.Offer(entities.User{ID: "u-alice", Username: "alice"}, "t-1", "bob")
+			if !errors.Is(err, tt.wantOffer) {
+				t.Fatalf("offer error = %v, want %v", err, tt.wantOffer)
+			}
+			if err != nil {
+				return
+			}
+			if !offer.Expires_at.Equal(tickets.ticket.Movie.Showtime) {
+				t.Errorf("offer expires %v, want the ticket's showtime %v", offer.Expires_at, tickets.ticket.Movie.Showtime)
+			}
+
+			if tt.lapse {
+				tickets.ticket.Movie.Showtime = now.Add(-time.Minute)
+			}
+			_, err = usecase.Accept(entities.User{ID: "u-bob", Username: "bob"}, offer.ID)
+			if !errors.Is(err, tt.wantAccept) {
+				t.Fatalf("accept error = %v, want %v", err, tt.wantAccept)
+			}
+			if tickets.moved != (tt.wantAccept == nil) {
+				t.Errorf("ticket moved = %v", tickets.moved)
+			}
+		})
+	}
+}
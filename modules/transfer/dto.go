@@ -0,0 +1,18 @@
+package transfer
+
+type (
+	SendRequest struct {
+		To_username string `json:"to_username" binding:"required"`
+		Amount      int    `json:"amount" binding:"required,min=1"`
+		Note        string `json:"note" binding:"max=140"`
+	}
+	ConfirmRequest struct {
+		Password string `json:"password" binding:"required"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
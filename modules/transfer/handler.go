@@ -0,0 +1,211 @@
+package transfer
+
+import (
+	"errors"
+	"net/http"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type handler struct {
+	transferUseCase UseCaseInterface
+	wallet          Wallet
+}
+
+type HandlerInterface interface {
+	SendTransfer(c *gin.Context)
+	ConfirmTransfer(c *gin.Context)
+	CancelTransfer(c *gin.Context)
+	GetTransfers(c *gin.Context)
+}
+
+func NewHandler(transferUseCase UseCaseInterface, wallet Wallet) HandlerInterface {
+	return &handler{
+		transferUseCase: transferUseCase,
+		wallet:          wallet,
+	}
+}
+
+func (h handler) SendTransfer(c *gin.Context) {
+	var req SendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.SendTransfer.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.SendTransfer.02")
+	if !ok {
+		return
+	}
+	transfer, err := h.transferUseCase.Send(user, req.To_username, req.Amount, req.Note)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.SendTransfer.03", "error", err)
+
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrDailyLimitExceeded) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_TRANSFER",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	if transfer.Status == entities.TransferPending {
+		c.JSON(http.StatusAccepted, Response{
+			Code:    http.StatusAccepted,
+			Message: "CONFIRMATION_REQUIRED",
+			Data:    transfer,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS_TRANSFER",
+		Data:    transfer,
+	})
+}
+
+// ConfirmTransfer asks for the password again before a large transfer goes
+// through, a stolen token alone is not enough to empty a wallet
+func (h handler) ConfirmTransfer(c *gin.Context) {
+	var req ConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.ConfirmTransfer.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.ConfirmTransfer.02")
+	if !ok {
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		logger.FromContext(c).Warn("Handler.ConfirmTransfer.03", "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "INVALID_PASSWORD",
+			Data:      nil,
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	transfer, err := h.transferUseCase.Confirm(user, c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.ConfirmTransfer.04", "error", err)
+
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, ErrTransferNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, ErrDailyLimitExceeded):
+			status = http.StatusForbidden
+		case errors.Is(err, ErrTransferNotPending), errors.Is(err, ErrConfirmationExpired):
+			status = http.StatusConflict
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_TRANSFER",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS_TRANSFER",
+		Data:    transfer,
+	})
+}
+
+func (h handler) CancelTransfer(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.CancelTransfer.01")
+	if !ok {
+		return
+	}
+	transfer, err := h.transferUseCase.Cancel(user, c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.CancelTransfer.02", "error", err)
+
+		status := http.StatusConflict
+		if errors.Is(err, ErrTransferNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_CANCEL",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS_CANCEL",
+		Data:    transfer,
+	})
+}
+
+func (h handler) GetTransfers(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.GetTransfers.01")
+	if !ok {
+		return
+	}
+	history, err := h.transferUseCase.GetHistory(user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetTransfers.02", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    history,
+	})
+}
+
+func (h handler) currentUser(c *gin.Context, tag string) (entities.User, bool) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.wallet.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error(tag, "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return entities.User{}, false
+	}
+	return user, true
+}
@@ -0,0 +1,13 @@
+package transfer
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+	UserRouter := r.Group("/user", middleware)
+	UserRouter.POST("/transfer", h.SendTransfer)
+	UserRouter.POST("/transfer/:id/confirm", h.ConfirmTransfer)
+	UserRouter.POST("/transfer/:id/cancel", h.CancelTransfer)
+	UserRouter.GET("/transfers", h.GetTransfers)
+}
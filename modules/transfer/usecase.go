@@ -0,0 +1,241 @@
+package transfer
+
+import (
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+// History directions
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+var (
+	ErrSelfTransfer        = errors.New("SELF_TRANSFER")
+	ErrNonPositiveValue    = errors.New("NON_POSITIVE_VALUE")
+	ErrDailyLimitExceeded  = errors.New("DAILY_LIMIT_EXCEEDED")
+	ErrTransferNotFound    = errors.New("TRANSFER_NOT_FOUND")
+	ErrTransferNotPending  = errors.New("TRANSFER_NOT_PENDING")
+	ErrConfirmationExpired = errors.New("CONFIRMATION_EXPIRED")
+)
+
+// Limits bound how much a user can send. Transfers above ConfirmThreshold
+// wait for the sender to confirm them within ConfirmWindow
+type Limits struct {
+	DailyLimit       int
+	ConfirmThreshold int
+	ConfirmWindow    time.Duration
+}
+
+// Wallet is the part of the user use case transfers move balance through
+type Wallet interface {
+	GetUser(username string) (entities.User, error)
+	MoveBalance(from, to string, n int) error
+	ReverseBalance(from, to string, n int) error
+}
+
+// HistoryEntry is a transfer seen from one side of it
+type HistoryEntry struct {
+	entities.Transfer
+	Direction string `json:"direction"`
+}
+
+type useCase struct {
+	// mu makes checking the daily limit and moving the balance one step
+	mu           sync.Mutex
+	transferRepo repositories.TransferRepositoryInterface
+	wallet       Wallet
+	limits       Limits
+}
+
+type UseCaseInterface interface {
+	Send(from entities.User, toUsername string, amount int, note string) (entities.Transfer, error)
+	Confirm(u entities.User, id string) (entities.Transfer, error)
+	Cancel(u entities.User, id string) (entities.Transfer, error)
+	GetHistory(u entities.User) ([]HistoryEntry, error)
+}
+
+func NewUseCase(transferRepo repositories.TransferRepositoryInterface, wallet Wallet, limits Limits) UseCaseInterface {
+	return &useCase{
+		transferRepo: transferRepo,
+		wallet:       wallet,
+		limits:       limits,
+	}
+}
+
+// Send moves amount to the recipient right away, or records a pending
+// transfer when the amount needs confirming
+func (usecase *useCase) Send(from entities.User, toUsername string, amount int, note string) (entities.Transfer, error) {
+	if amount <= 0 {
+		return entities.Transfer{}, ErrNonPositiveValue
+	}
+	if toUsername == from.Username {
+		return entities.Transfer{}, ErrSelfTransfer
+	}
+	to, err := usecase.wallet.GetUser(toUsername)
+	if err != nil {
+		return entities.Transfer{}, err
+	}
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		return entities.Transfer{}, err
+	}
+
+	now := time.Now()
+	transfer := entities.Transfer{
+		ID:            UUID.String(),
+		From_user_id:  from.ID,
+		From_username: from.Username,
+		To_user_id:    to.ID,
+		To_username:   to.Username,
+		Amount:        amount,
+		Note:          note,
+		Status:        entities.TransferPending,
+		Created_at:    now,
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	if err := usecase.checkDailyLimit(from.ID, amount, now); err != nil {
+		return entities.Transfer{}, err
+	}
+	if amount > usecase.limits.ConfirmThreshold {
+		transfer.Expires_at = now.Add(usecase.limits.ConfirmWindow)
+		if err := usecase.transferRepo.Create(transfer); err != nil {
+			return entities.Transfer{}, err
+		}
+		return transfer, nil
+	}
+	return usecase.complete(transfer, now, true)
+}
+
+// Confirm completes a pending transfer of the user. The daily limit is
+// checked again since other transfers may have completed in the meantime
+func (usecase *useCase) Confirm(u entities.User, id string) (entities.Transfer, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	transfer, err := usecase.pending(u, id)
+	if err != nil {
+		return entities.Transfer{}, err
+	}
+	now := time.Now()
+	if now.After(transfer.Expires_at) {
+		transfer.Status = entities.TransferExpired
+		usecase.transferRepo.Update(transfer)
+		return entities.Transfer{}, ErrConfirmationExpired
+	}
+	if err := usecase.checkDailyLimit(u.ID, transfer.Amount, now); err != nil {
+		return entities.Transfer{}, err
+	}
+	return usecase.complete(transfer, now, false)
+}
+
+func (usecase *useCase) Cancel(u entities.User, id string) (entities.Transfer, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	transfer, err := usecase.pending(u, id)
+	if err != nil {
+		return entities.Transfer{}, err
+	}
+	transfer.Status = entities.TransferCancelled
+	if err := usecase.transferRepo.Update(transfer); err != nil {
+		return entities.Transfer{}, err
+	}
+	return transfer, nil
+}
+
+// GetHistory lists the transfers the user sent or received, newest first.
+// Pending transfers past their window are reported as expired
+func (usecase *useCase) GetHistory(u entities.User) ([]HistoryEntry, error) {
+	transfers, err := usecase.transferRepo.ReadByUser(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	history := make([]HistoryEntry, 0, len(transfers))
+	for _, transfer := range transfers {
+		// Only the sender knows about a transfer before it completes
+		if transfer.From_user_id != u.ID && transfer.Status != entities.TransferCompleted {
+			continue
+		}
+		if transfer.Status == entities.TransferPending && now.After(transfer.Expires_at) {
+			transfer.Status = entities.TransferExpired
+		}
+		direction := DirectionSent
+		if transfer.To_user_id == u.ID {
+			direction = DirectionReceived
+		}
+		history = append(history, HistoryEntry{Transfer: transfer, Direction: direction})
+	}
+	sort.SliceStable(history, func(a, b int) bool {
+		return history[a].Created_at.After(history[b].Created_at)
+	})
+	return history, nil
+}
+
+func (usecase *useCase) pending(u entities.User, id string) (entities.Transfer, error) {
+	transfer, err := usecase.transferRepo.Read(id)
+	if err != nil || transfer.From_user_id != u.ID {
+		return entities.Transfer{}, ErrTransferNotFound
+	}
+	if transfer.Status != entities.TransferPending {
+		return entities.Transfer{}, ErrTransferNotPending
+	}
+	return transfer, nil
+}
+
+// complete moves the balance and stores the transfer as completed, create
+// tells whether the transfer is new or already stored as pending
+func (usecase *useCase) complete(transfer entities.Transfer, now time.Time, create bool) (entities.Transfer, error) {
+	if err := usecase.wallet.MoveBalance(transfer.From_username, transfer.To_username, transfer.Amount); err != nil {
+		return entities.Transfer{}, err
+	}
+	transfer.Status = entities.TransferCompleted
+	transfer.Completed_at = now
+	transfer.Expires_at = time.Time{}
+
+	save := usecase.transferRepo.Update
+	if create {
+		save = usecase.transferRepo.Create
+	}
+	if err := save(transfer); err != nil {
+		if err := usecase.wallet.ReverseBalance(transfer.From_username, transfer.To_username, transfer.Amount); err != nil {
+			slog.Error("UseCase.Transfer.Complete.01", "error", err, "transfer_id", transfer.ID)
+		}
+		return entities.Transfer{}, err
+	}
+	return transfer, nil
+}
+
+// checkDailyLimit adds amount to what the user already sent since midnight
+func (usecase *useCase) checkDailyLimit(userID string, amount int, now time.Time) error {
+	transfers, err := usecase.transferRepo.ReadByUser(userID)
+	if err != nil {
+		return err
+	}
+	year, month, day := now.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	sent := amount
+	for _, transfer := range transfers {
+		if transfer.From_user_id == userID && transfer.Status == entities.TransferCompleted && !transfer.Completed_at.Before(midnight) {
+			sent += transfer.Amount
+		}
+	}
+	if sent > usecase.limits.DailyLimit {
+		return ErrDailyLimitExceeded
+	}
+	return nil
+}
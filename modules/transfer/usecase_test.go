@@ -0,0 +1,115 @@
+package transfer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/repositories"
+)
+
+// fakeWallet moves balance without checking it, the limit is what is tested
+type fakeWallet struct {
+	moved int
+}
+
+func (w *fakeWallet) GetUser(username string) (entities.User, error) {
+	return entities.User{ID: "u-" + username, Username: username}, nil
+}
+
+func (w *fakeWallet) MoveBalance(from, to string, n int) error {
+	w.moved += n
+	return nil
+}
+
+func (w *fakeWallet) ReverseBalance(from, to string, n int) error {
+	w.moved -= n
+	return nil
+}
+
+var alice = entities.User{ID: "u-alice", Username: "alice"}
+
+// step sends amount to bob, or confirms alice's first pending transfer
+type step struct {
+	amount  int
+	confirm bool
+	want    error
+}
+
+func TestDailyLimit(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	tests := []struct {
+		name string
+		// threshold is the amount above which transfers wait to be confirmed
+		threshold int
+		sent      []entities.Transfer
+		steps     []step
+		wantMoved int
+	}{
+		{
+			name:      "sends add up to the limit",
+			threshold: 1000,
+			steps:     []step{{amount: 600}, {amount: 400}, {amount: 1, want: ErrDailyLimitExceeded}},
+			wantMoved: 1000,
+		},
+		{
+			name:      "one send above the limit",
+			threshold: 2000,
+			steps:     []step{{amount: 1001, want: ErrDailyLimitExceeded}},
+		},
+		{
+			name:      "yesterday's transfers do not count",
+			threshold: 1000,
+			sent: []entities.Transfer{{
+				ID: "t-old", From_user_id: alice.ID, Amount: 1000,
+				Status: entities.TransferCompleted, Created_at: yesterday, Completed_at: yesterday,
+			}},
+			steps:     []step{{amount: 1000}},
+			wantMoved: 1000,
+		},
+		{
+			name:      "pending transfers count once confirmed",
+			threshold: 500,
+			steps:     []step{{amount: 800}, {amount: 400}, {confirm: true, want: ErrDailyLimitExceeded}},
+			wantMoved: 400,
+		},
+		{
+			name:      "confirmed within the limit",
+			threshold: 500,
+			steps:     []step{{amount: 800}, {amount: 200}, {confirm: true}},
+			wantMoved: 1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := &fakeWallet{}
+			usecase := NewUseCase(repositories.NewTransferRepository(tt.sent), wallet, Limits{
+				DailyLimit:       1000,
+				ConfirmThreshold: tt.threshold,
+				ConfirmWindow:    time.Hour,
+			})
+			var pending string
+			for i, s := range tt.steps {
+				var (
+					transfer entities.Transfer
+					err      error
+				)
+				if s.confirm {
+					transfer, err = usecase.Confirm(alice, pending)
+				} else {
+					transfer, err = usecase.Send(alice, "bob", s.amount, "")
+				}
+				if !errors.Is(err, s.want) {
+					t.Fatalf("step %d: error = %v, want %v", i, err, s.want)
+				}
+				if transfer.Status == entities.TransferPending && pending == "" {
+					pending = transfer.ID
+				}
+			}
+			if wallet.moved != tt.wantMoved {
+				t.Errorf("moved %d, want %d", wallet.moved, tt.wantMoved)
+			}
+		})
+	}
+}
@@ -2,33 +2,64 @@ package user
 
 import "movie-app-go/entities"
 
+// DateLayout is how dates of birth are sent and received
+const DateLayout = "2006-01-02"
+
 type RequestInterface interface {
 	Validate() interface{}
 }
 
 type (
 	Register struct {
-		Username string `json:"username" binding:"required"`
-		Password string `json:"password" binding:"required"`
-		Name     string `json:"name" binding:"required"`
-		Age      int    `json:"age" binding:"required,number"`
+		Username  string `json:"username" binding:"required"`
+		Password  string `json:"password" binding:"required"`
+		Name      string `json:"name" binding:"required"`
+		Birthdate string `json:"birthdate" binding:"required,datetime=2006-01-02"`
 	}
 	Login struct {
 		Username string `json:"username" binding:"required"`
 		Password string `json:"password" binding:"required"`
 	}
 	BuyTicketRequest struct {
+		Seats      []entities.Seat `json:"seats" binding:"required_without=Auto"`
+		Auto       *AutoSeats      `json:"auto"`
+		Promo_code string          `json:"promo_code" binding:"omitempty,alphanum,max=32"`
+		Quote_id   string          `json:"quote_id" binding:"omitempty,uuid"`
+		Points     int             `json:"points" binding:"min=0"`
+		Attendees  []Attendee      `json:"attendees" binding:"dive"`
+	}
+	// Attendee seats a profile, the purchaser's or a dependent's. Without a
+	// seat the profile takes the next seat nobody was assigned to, which
+	// suits automatically picked seats
+	Attendee struct {
+		Seat       string `json:"seat"`
+		Profile_id string `json:"profile_id" binding:"required"`
+	}
+	AutoSeats struct {
+		PartySize      int    `json:"party_size" binding:"required,min=1"`
+		PreferredRow   string `json:"preferred_row"`
+		ContiguousOnly bool   `json:"contiguous_only"`
+	}
+	SeatsRequest struct {
 		Seats []entities.Seat `json:"seats" binding:"required"`
 	}
+	HoldResponse struct {
+		Seats []entities.Seat     `json:"seats"`
+		Quote entities.PriceQuote `json:"quote"`
+	}
 	Balance struct {
 		Amount int `json:"amount" binding:"required,number"`
 	}
+	BirthdateRequest struct {
+		Birthdate string `json:"birthdate" binding:"required,datetime=2006-01-02"`
+	}
 	TicketRequest struct {
 		ID string `json:"id" binding:"required"`
 	}
 	Response struct {
-		Code    int    `json:"code" binding:"required"`
-		Message string `json:"message" binding:"required"`
-		Data    any    `json:"data" binding:"required"`
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
 	}
 )
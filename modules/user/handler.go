@@ -2,13 +2,16 @@ package user
 
 import (
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"movie-app-go/entities"
 	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+	"movie-app-go/modules/seating"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,6 +31,10 @@ type HandlerInterface interface {
 	GetUser(c *gin.Context)
 	TopUp(c *gin.Context)
 	Withdraw(c *gin.Context)
+	HoldSeats(c *gin.Context)
+	ReleaseSeats(c *gin.Context)
+	UpdateBirthdate(c *gin.Context)
+	VerifyID(c *gin.Context)
 }
 
 func NewHandler(userUseCase UseCaseInterface, auth auth.AuthInterface) HandlerInterface {
@@ -40,34 +47,38 @@ func NewHandler(userUseCase UseCaseInterface, auth auth.AuthInterface) HandlerIn
 func (h handler) Register(c *gin.Context) {
 	var req Register
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Println("Handler.Register.01", err.Error())
+		logger.FromContext(c).Error("Handler.Register.01", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "INVALID_REQUEST",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
+	birthdate, _ := time.Parse(DateLayout, req.Birthdate)
 	UUID, err := uuid.NewRandom()
 	if err != nil {
-		log.Println("Handler.Register.02", err.Error())
+		logger.FromContext(c).Error("Handler.Register.02", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "FAILED_GENERATE_UUID",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_GENERATE_UUID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Println("Handler.Register.03", err.Error())
+		logger.FromContext(c).Error("Handler.Register.03", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "FAILED_HASH_PASSWORD",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_HASH_PASSWORD",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -76,17 +87,19 @@ func (h handler) Register(c *gin.Context) {
 		Username:   req.Username,
 		Password:   string(hashedPassword),
 		Name:       req.Name,
-		Age:        req.Age,
+		Birthdate:  birthdate,
+		Role:       entities.RoleUser,
 		Created_at: time.Now(),
 		Updated_at: time.Now(),
 	}
 	if err := h.userUseCase.Create(newUser); err != nil {
-		log.Println("Handler.Register.04", err.Error())
+		logger.FromContext(c).Error("Handler.Register.04", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "FAILED_USECASE",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -102,65 +115,150 @@ func (h handler) GetUser(c *gin.Context) {
 	authInfo, _ := c.Get("AuthInfo")
 	user, err := h.userUseCase.GetUser(authInfo.(auth.AuthInfo).Username)
 	if err != nil {
-		log.Println("Handler.GetUser.01", err.Error())
+		logger.FromContext(c).Error("Handler.GetUser.01", "error", err)
 
 		c.JSON(http.StatusUnauthorized, Response{
-			Code:    http.StatusUnauthorized,
-			Message: "UNAUTHORIZED",
-			Data:    err.Error(),
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
+	// Report the current age rather than the one registered with
+	user.Age = user.AgeAt(time.Now())
 	c.JSON(http.StatusOK, Response{
 		Code:    http.StatusOK,
 		Message: "SUCCESS",
 		Data:    user,
 	})
 }
+
+func (h handler) UpdateBirthdate(c *gin.Context) {
+	var req BirthdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.UpdateBirthdate.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	birthdate, _ := time.Parse(DateLayout, req.Birthdate)
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.userUseCase.SetBirthdate(authInfo.(auth.AuthInfo).Username, birthdate)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.UpdateBirthdate.02", "error", err)
+
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrBirthdateLocked) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_UPDATE_BIRTHDATE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "BIRTHDATE_UPDATED",
+		Data:    user,
+	})
+}
+
+// VerifyID is called by staff after checking an ID document, the date of
+// birth on it replaces the one the user entered
+func (h handler) VerifyID(c *gin.Context) {
+	var req BirthdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.VerifyID.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	birthdate, _ := time.Parse(DateLayout, req.Birthdate)
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.userUseCase.VerifyID(c.Param("username"), authInfo.(auth.AuthInfo).Username, birthdate)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.VerifyID.02", "error", err)
+
+		status := http.StatusBadRequest
+		if !errors.Is(err, ErrInvalidBirthdate) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_VERIFY_ID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "ID_VERIFIED",
+		Data:    user,
+	})
+}
 func (h handler) Login(c *gin.Context) {
 	var req Login
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Println("Handler.Login.01", err.Error())
+		logger.FromContext(c).Error("Handler.Login.01", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "INVALID_REQUEST",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
 	user, err := h.userUseCase.GetUser(req.Username)
 	if err != nil {
-		log.Println("Handler.Login.02", err.Error())
+		logger.FromContext(c).Error("Handler.Login.02", "error", err)
 
 		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "USERNAME_NOT_FOUND",
-			Data:    err.Error(),
+			Code:      http.StatusNotFound,
+			Message:   "USERNAME_NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		log.Println("Handler.Login.03", err.Error())
+		logger.FromContext(c).Error("Handler.Login.03", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "PASSWORD_NOT_MATCH",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "PASSWORD_NOT_MATCH",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
-	token, err := h.auth.GenerateToken(user.Username)
+	token, err := h.auth.GenerateToken(user.Username, user.Role)
 	if err != nil {
-		log.Println("Handler.Login.04", err.Error())
+		logger.FromContext(c).Error("Handler.Login.04", "error", err)
 
 		c.JSON(http.StatusInternalServerError, Response{
-			Code:    http.StatusInternalServerError,
-			Message: "FAILED_GENERATE_TOKEN",
-			Data:    err.Error(),
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_GENERATE_TOKEN",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -175,12 +273,13 @@ func (h handler) BuyTicket(c *gin.Context) {
 	var req BuyTicketRequest
 	movieId := c.Param("movie_id")
 	if movieId == "" {
-		log.Println("Handler.BuyTicket.01", errors.New("BAD_REQUEST"))
+		logger.FromContext(c).Error("Handler.BuyTicket.01", "error", errors.New("BAD_REQUEST"))
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "INVALID_REQUEST",
-			Data:    errors.New("BAD_REQUEST"),
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      errors.New("BAD_REQUEST"),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -188,123 +287,264 @@ func (h handler) BuyTicket(c *gin.Context) {
 	authInfo, _ := c.Get("AuthInfo")
 	user, err := h.userUseCase.GetUser(authInfo.(auth.AuthInfo).Username)
 	if err != nil {
-		log.Println("Handler.BuyTicket.02", err.Error())
+		logger.FromContext(c).Error("Handler.BuyTicket.02", "error", err)
 
 		c.JSON(http.StatusUnauthorized, Response{
-			Code:    http.StatusUnauthorized,
-			Message: "UNAUTHORIZED",
-			Data:    err.Error(),
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
 	movie, err := h.userUseCase.GetMovie(mid)
 	if err != nil {
-		log.Println("Handler.BuyTicket.03", err.Error())
+		logger.FromContext(c).Error("Handler.BuyTicket.03", "error", err)
 
 		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "NOT_FOUND",
-			Data:    err.Error(),
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
-	// Check age
-	if h.userUseCase.NotPermitted(movie, user) {
-		log.Println("Handler.BuyTicket.04", errors.New("AGE_RESTRICTION"))
+	if err := h.userUseCase.OnSale(movie, time.Now()); err != nil {
+		logger.FromContext(c).Error("Handler.BuyTicket.17", "error", err)
 
-		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "AGE_RESTRICTION",
-			Data:    errors.New("AGE_RESTRICTION"),
+		c.JSON(http.StatusForbidden, Response{
+			Code:      http.StatusForbidden,
+			Message:   "NOT_ON_SALE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	// Select Seats
 	if err := c.ShouldBindJSON(&req); err != nil {
 		if err != nil {
-			log.Println("Handler.BuyTicket.05", err.Error())
+			logger.FromContext(c).Error("Handler.BuyTicket.05", "error", err)
+
+			c.JSON(http.StatusBadRequest, Response{
+				Code:      http.StatusBadRequest,
+				Message:   "FAILED_BINDING_REQUEST",
+				Data:      err.Error(),
+				RequestID: logger.RequestID(c),
+			})
+			return
+		}
+	}
+	// Pick seats for the client
+	if req.Auto != nil {
+		suggested, err := h.userUseCase.SuggestSeats(movie, user, seating.Options{
+			PartySize:      req.Auto.PartySize,
+			PreferredRow:   req.Auto.PreferredRow,
+			ContiguousOnly: req.Auto.ContiguousOnly,
+		})
+		if err != nil {
+			logger.FromContext(c).Error("Handler.BuyTicket.11", "error", err)
 
 			c.JSON(http.StatusBadRequest, Response{
-				Code:    http.StatusBadRequest,
-				Message: "FAILED_BINDING_REQUEST",
-				Data:    err.Error(),
+				Code:      http.StatusBadRequest,
+				Message:   "FAILED_AUTO_SEATS",
+				Data:      err.Error(),
+				RequestID: logger.RequestID(c),
 			})
 			return
 		}
+		req.Seats = suggested
 	}
 	// Check available seats
-	seats, err := h.userUseCase.CheckAvailability(req.Seats, &movie)
+	seats, err := h.userUseCase.CheckAvailability(req.Seats, &movie, user)
 	if err != nil {
-		log.Println("Handler.BuyTicket.06", err.Error())
+		logger.FromContext(c).Error("Handler.BuyTicket.06", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "FAILED_BOOKED",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BOOKED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	// Check age of everyone attending
+	profiles, err := attendeeProfiles(seats, req.Attendees)
+	var attendees []entities.Attendee
+	if err == nil {
+		attendees, err = h.userUseCase.AssignAttendees(user, movie, seats, profiles)
+	}
+	if err != nil {
+		logger.FromContext(c).Error("Handler.BuyTicket.04", "error", err)
+
+		status, message := http.StatusBadRequest, "INVALID_ATTENDEE"
+		if errors.Is(err, ErrAgeRestriction) {
+			status, message = http.StatusNotFound, "AGE_RESTRICTION"
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   message,
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 
 	// Check Balance
-	costs := movie.Ticket_price * len(seats)
+	breakdown := h.userUseCase.QuotePrice(movie, seats)
+	if req.Quote_id != "" {
+		breakdown, err = h.userUseCase.UseQuote(req.Quote_id, user, movie, seats)
+		if err != nil {
+			logger.FromContext(c).Error("Handler.BuyTicket.14", "error", err)
+
+			c.JSON(http.StatusBadRequest, Response{
+				Code:      http.StatusBadRequest,
+				Message:   "INVALID_QUOTE",
+				Data:      err.Error(),
+				RequestID: logger.RequestID(c),
+			})
+			return
+		}
+	}
+	if req.Promo_code != "" {
+		if err := h.userUseCase.ApplyPromo(req.Promo_code, user, movie, &breakdown); err != nil {
+			logger.FromContext(c).Error("Handler.BuyTicket.12", "error", err)
+
+			c.JSON(http.StatusBadRequest, Response{
+				Code:      http.StatusBadRequest,
+				Message:   "INVALID_PROMO_CODE",
+				Data:      err.Error(),
+				RequestID: logger.RequestID(c),
+			})
+			return
+		}
+	}
+	if req.Points > 0 {
+		if err := h.userUseCase.ApplyPoints(user, req.Points, &breakdown); err != nil {
+			logger.FromContext(c).Error("Handler.BuyTicket.15", "error", err)
+
+			c.JSON(http.StatusBadRequest, Response{
+				Code:      http.StatusBadRequest,
+				Message:   "INVALID_POINTS",
+				Data:      err.Error(),
+				RequestID: logger.RequestID(c),
+			})
+			return
+		}
+	}
+	costs := breakdown.Total
 	if err := h.userUseCase.CheckBalance(user, costs); err != nil {
-		log.Println("Handler.BuyTicket.07", err)
+		logger.FromContext(c).Error("Handler.BuyTicket.07", "error", err)
+		if errors.Is(err, ErrSpendingLimit) {
+			c.JSON(http.StatusForbidden, Response{
+				Code:      http.StatusForbidden,
+				Message:   "SPENDING_LIMIT_REACHED",
+				Data:      err.Error(),
+				RequestID: logger.RequestID(c),
+			})
+			return
+		}
 
 		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "NOT_ENOUGH_BALANCE",
-			Data:    err,
+			Code:      http.StatusNotFound,
+			Message:   "NOT_ENOUGH_BALANCE",
+			Data:      err,
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
-	if err := h.userUseCase.Withdraw(&user, costs); err != nil {
-		log.Println("Handler.BuyTicket.08", err)
 
-		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "NOT_ENOUGH_BALANCE",
-			Data:    err,
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		logger.FromContext(c).Error("Handler.BuyTicket.09", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_GENERATE_UUID",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
-	for i1, s1 := range seats {
-		for i2, s2 := range movie.Seats {
-			if s1 == s2 {
-				seats[i1].Booked = true
-				movie.Seats[i2].Booked = true
-			}
-		}
-	}
+	if err := h.userUseCase.RedeemPromo(user, movie, UUID.String(), breakdown); err != nil {
+		logger.FromContext(c).Error("Handler.BuyTicket.13", "error", err)
 
-	UUID, err := uuid.NewRandom()
-	if err != nil {
-		log.Println("Handler.BuyTicket.09", err.Error())
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_PROMO_CODE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if err := h.userUseCase.RedeemPoints(user, UUID.String(), breakdown); err != nil {
+		logger.FromContext(c).Error("Handler.BuyTicket.16", "error", err)
+		h.userUseCase.ReleasePromo(UUID.String())
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "FAILED_GENERATE_UUID",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_POINTS",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	// Take the seats before paying, someone may have booked them since
+	// they were checked
+	seats, err = h.userUseCase.BookSeats(seats, &movie, user.Username)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.BuyTicket.18", "error", err)
+		h.userUseCase.ReleasePromo(UUID.String())
+		h.userUseCase.ReversePoints(UUID.String())
+
+		c.JSON(http.StatusConflict, Response{
+			Code:      http.StatusConflict,
+			Message:   "FAILED_BOOKED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
+	if err := h.userUseCase.Withdraw(&user, costs); err != nil {
+		logger.FromContext(c).Error("Handler.BuyTicket.08", "error", err)
+		h.userUseCase.FreeSeats(seats, &movie)
+		h.userUseCase.ReleasePromo(UUID.String())
+		h.userUseCase.ReversePoints(UUID.String())
 
+		c.JSON(http.StatusNotFound, Response{
+			Code:      http.StatusNotFound,
+			Message:   "NOT_ENOUGH_BALANCE",
+			Data:      err,
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
 	newTicket := entities.Ticket{
 		ID:         UUID.String(),
 		Movie:      movie,
 		UserID:     user.ID,
 		Seats:      seats,
 		Cost:       costs,
+		Breakdown:  breakdown,
+		Attendees:  attendees,
 		Created_At: time.Now(),
 		Updated_At: time.Now(),
 	}
 	if err := h.userUseCase.BuyTicket(user, newTicket); err != nil {
-		log.Println("Handler.BuyTicket.10", err.Error())
+		logger.FromContext(c).Error("Handler.BuyTicket.10", "error", err)
+		// Unwind everything the purchase took so far
+		h.userUseCase.FreeSeats(seats, &movie)
+		if err := h.userUseCase.TopUp(&user, costs); err != nil {
+			logger.FromContext(c).Error("Handler.BuyTicket.19", "error", err, "ticket_id", newTicket.ID)
+		}
+		h.userUseCase.ReleasePromo(UUID.String())
+		h.userUseCase.ReversePoints(UUID.String())
 
 		c.JSON(http.StatusInternalServerError, Response{
-			Code:    http.StatusInternalServerError,
-			Message: "FAILED_CREATE_TICKET",
-			Data:    err.Error(),
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_CREATE_TICKET",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -319,34 +559,37 @@ func (h handler) BuyTicket(c *gin.Context) {
 func (h handler) TopUp(c *gin.Context) {
 	var req Balance
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Println("Handler.TopUp.01", err.Error())
+		logger.FromContext(c).Error("Handler.TopUp.01", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "BAD_REQUEST",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	authInfo, _ := c.Get("AuthInfo")
 	user, err := h.userUseCase.GetUser(authInfo.(auth.AuthInfo).Username)
 	if err != nil {
-		log.Println("Handler.TopUp.02", err.Error())
+		logger.FromContext(c).Error("Handler.TopUp.02", "error", err)
 
 		c.JSON(http.StatusUnauthorized, Response{
-			Code:    http.StatusUnauthorized,
-			Message: "UNAUTHORIZED",
-			Data:    err.Error(),
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	if err := h.userUseCase.TopUp(&user, req.Amount); err != nil {
-		log.Println("Handler.TopUp.03", err.Error())
+		logger.FromContext(c).Error("Handler.TopUp.03", "error", err)
 
 		c.JSON(http.StatusInternalServerError, Response{
-			Code:    http.StatusInternalServerError,
-			Message: "FAILED_TOP_UP",
-			Data:    err.Error(),
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_TOP_UP",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -360,34 +603,37 @@ func (h handler) TopUp(c *gin.Context) {
 func (h handler) Withdraw(c *gin.Context) {
 	var req Balance
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Println("Handler.Withdraw.01", err.Error())
+		logger.FromContext(c).Error("Handler.Withdraw.01", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "BAD_REQUEST",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	authInfo, _ := c.Get("AuthInfo")
 	user, err := h.userUseCase.GetUser(authInfo.(auth.AuthInfo).Username)
 	if err != nil {
-		log.Println("Handler.Withdraw.02", err.Error())
+		logger.FromContext(c).Error("Handler.Withdraw.02", "error", err)
 
 		c.JSON(http.StatusUnauthorized, Response{
-			Code:    http.StatusUnauthorized,
-			Message: "UNAUTHORIZED",
-			Data:    err.Error(),
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	if err := h.userUseCase.Withdraw(&user, req.Amount); err != nil {
-		log.Println("Handler.Withdraw.03", err.Error())
+		logger.FromContext(c).Error("Handler.Withdraw.03", "error", err)
 
 		c.JSON(http.StatusInternalServerError, Response{
-			Code:    http.StatusInternalServerError,
-			Message: "FAILED_WITHDRAW",
-			Data:    err.Error(),
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_WITHDRAW",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -401,65 +647,101 @@ func (h handler) CancelTicket(c *gin.Context) {
 	authInfo, _ := c.Get("AuthInfo")
 	user, err := h.userUseCase.GetUser(authInfo.(auth.AuthInfo).Username)
 	if err != nil {
-		log.Println("Handler.CancelTicket.01", err.Error())
+		logger.FromContext(c).Error("Handler.CancelTicket.01", "error", err)
 
 		c.JSON(http.StatusUnauthorized, Response{
-			Code:    http.StatusUnauthorized,
-			Message: "UNAUTHORIZED",
-			Data:    err.Error(),
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	var req TicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Println("Handler.CancelTicket.02", err.Error())
+		logger.FromContext(c).Error("Handler.CancelTicket.02", "error", err)
 
 		c.JSON(http.StatusBadRequest, Response{
-			Code:    http.StatusBadRequest,
-			Message: "BAD_REQUEST",
-			Data:    err.Error(),
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
 	ticket, err := h.userUseCase.GetTicket(req.ID)
+	if err == nil && ticket.UserID != user.ID {
+		err = errors.New("NOT_FOUND")
+	}
 	if err != nil {
-		log.Println("Handler.CancelTicket.03", err.Error())
+		logger.FromContext(c).Error("Handler.CancelTicket.03", "error", err)
 
 		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "NOT_FOUND",
-			Data:    err.Error(),
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
-	seats := ticket.Seats
-	movie, _ := h.userUseCase.GetMovie(ticket.Movie.ID)
-	for i1, s1 := range seats {
-		for i2, s2 := range movie.Seats {
-			if s1 == s2 {
-				seats[i1].Booked = false
-				movie.Seats[i2].Booked = false
-			}
-		}
+	if ticket.CheckedIn() {
+		logger.FromContext(c).Warn("Handler.CancelTicket.04", "ticket_id", ticket.ID)
+
+		c.JSON(http.StatusConflict, Response{
+			Code:      http.StatusConflict,
+			Message:   "TICKET_ALREADY_USED",
+			Data:      nil,
+			RequestID: logger.RequestID(c),
+		})
+		return
 	}
-	costs := ticket.Cost
-	if err := h.userUseCase.TopUp(&user, costs); err != nil {
-		log.Println("Handler.BuyTicket.08", err)
+	if !ticket.Movie.Showtime.IsZero() && !time.Now().Before(ticket.Movie.Showtime) {
+		logger.FromContext(c).Warn("Handler.CancelTicket.06", "ticket_id", ticket.ID)
+
+		c.JSON(http.StatusConflict, Response{
+			Code:      http.StatusConflict,
+			Message:   "SHOW_ALREADY_STARTED",
+			Data:      nil,
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	// A transferred ticket is refunded to whoever paid for it
+	payer := user
+	if len(ticket.PreviousOwners) > 0 {
+		payer, err = h.userUseCase.GetUser(ticket.PreviousOwners[0].Username)
+	}
+	if err != nil {
+		logger.FromContext(c).Error("Handler.CancelTicket.05", "error", err)
 
 		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "NOT_ENOUGH_BALANCE",
-			Data:    err,
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
+	// Cancel before refunding, a ticket that stays valid is never paid back
 	if err := h.userUseCase.CancelTicket(user, ticket); err != nil {
-		log.Println("Handler.CancelTicket.03", err.Error())
+		logger.FromContext(c).Error("Handler.CancelTicket.03", "error", err)
 
 		c.JSON(http.StatusNotFound, Response{
-			Code:    http.StatusNotFound,
-			Message: "NOT_FOUND",
-			Data:    err.Error(),
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if err := h.userUseCase.TopUp(&payer, ticket.Cost); err != nil {
+		logger.FromContext(c).Error("Handler.CancelTicket.07", "error", err, "ticket_id", ticket.ID, "payer", payer.Username, "amount", ticket.Cost)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_REFUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
 		})
 		return
 	}
@@ -469,3 +751,196 @@ func (h handler) CancelTicket(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+func (h handler) HoldSeats(c *gin.Context) {
+	mid, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.HoldSeats.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.userUseCase.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.HoldSeats.02", "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	movie, err := h.userUseCase.GetMovie(mid)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.HoldSeats.03", "error", err)
+
+		c.JSON(http.StatusNotFound, Response{
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	if err := h.userUseCase.OnSale(movie, time.Now()); err != nil {
+		logger.FromContext(c).Error("Handler.HoldSeats.07", "error", err)
+
+		c.JSON(http.StatusForbidden, Response{
+			Code:      http.StatusForbidden,
+			Message:   "NOT_ON_SALE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	var req SeatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.HoldSeats.04", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BINDING_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	seats, err := h.userUseCase.HoldSeats(req.Seats, &movie, user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.HoldSeats.05", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_HOLD",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	quote, err := h.userUseCase.LockQuote(user, movie, seats)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.HoldSeats.06", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_QUOTE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SEATS_HELD",
+		Data: HoldResponse{
+			Seats: seats,
+			Quote: quote,
+		},
+	})
+}
+
+func (h handler) ReleaseSeats(c *gin.Context) {
+	mid, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.ReleaseSeats.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.userUseCase.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.ReleaseSeats.02", "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	movie, err := h.userUseCase.GetMovie(mid)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.ReleaseSeats.03", "error", err)
+
+		c.JSON(http.StatusNotFound, Response{
+			Code:      http.StatusNotFound,
+			Message:   "NOT_FOUND",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	var req SeatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.ReleaseSeats.04", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_BINDING_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	seats, err := h.userUseCase.ReleaseSeats(req.Seats, &movie, user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.ReleaseSeats.05", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "FAILED_RELEASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SEATS_RELEASED",
+		Data:    seats,
+	})
+}
+
+// attendeeProfiles maps seat labels to the profile sitting there. Profiles
+// sent without a seat fill the seats left over in order
+func attendeeProfiles(seats []entities.Seat, attendees []Attendee) (map[string]string, error) {
+	if len(attendees) > len(seats) {
+		return nil, fmt.Errorf("%w: %d attendees for %d seats", ErrInvalidAttendee, len(attendees), len(seats))
+	}
+	profiles := make(map[string]string, len(attendees))
+	var unseated []string
+	for _, attendee := range attendees {
+		if attendee.Seat == "" {
+			unseated = append(unseated, attendee.Profile_id)
+			continue
+		}
+		profiles[strings.ToUpper(attendee.Seat)] = attendee.Profile_id
+	}
+	for _, seat := range seats {
+		if len(unseated) == 0 {
+			break
+		}
+		label := fmt.Sprintf("%s%d", seat.Row, seat.Number)
+		if _, ok := profiles[label]; ok {
+			continue
+		}
+		profiles[label] = unseated[0]
+		unseated = unseated[1:]
+	}
+	return profiles, nil
+}
@@ -4,15 +4,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc, staffOnly gin.HandlerFunc) {
 	UserRouter := r.Group("/user")
 	UserRouter.POST("/register", h.Register)
 	UserRouter.POST("/login", h.Login)
 
 	UserRouter.POST("/buy-ticket/:movie_id", middleware, h.BuyTicket)
 	UserRouter.POST("/cancel-ticket", middleware, h.CancelTicket)
+	UserRouter.POST("/hold-seats/:movie_id", middleware, h.HoldSeats)
+	UserRouter.POST("/release-seats/:movie_id", middleware, h.ReleaseSeats)
 
 	UserRouter.GET("/me", middleware, h.GetUser)
 	UserRouter.POST("/top-up", middleware, h.TopUp)
 	UserRouter.POST("/withdraw", middleware, h.Withdraw)
+	UserRouter.PUT("/birthdate", middleware, h.UpdateBirthdate)
+
+	StaffRouter := r.Group("/staff/users", middleware, staffOnly)
+	StaffRouter.POST("/:username/verify-id", h.VerifyID)
 }
@@ -1,16 +1,62 @@
 package user
 
 import (
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"movie-app-go/entities"
+	"movie-app-go/modules/loyalty"
+	"movie-app-go/modules/pricing"
+	"movie-app-go/modules/promo"
+	"movie-app-go/modules/realtime"
+	"movie-app-go/modules/seating"
 	"movie-app-go/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxAge = 130
+
+var (
+	ErrInvalidBirthdate = errors.New("INVALID_BIRTHDATE")
+	ErrBirthdateLocked  = errors.New("BIRTHDATE_LOCKED")
+	ErrSpendingLimit    = errors.New("SPENDING_LIMIT_REACHED")
+	ErrInvalidAttendee  = errors.New("INVALID_ATTENDEE")
+	ErrAgeRestriction   = errors.New("AGE_RESTRICTION")
+	ErrNotOnSale        = errors.New("NOT_ON_SALE")
+	ErrShowStarted      = errors.New("SHOW_ALREADY_STARTED")
 )
 
 type useCase struct {
-	userRepo   repositories.UserRepositoryInterface
-	movieRepo  repositories.MovieRepositoryInterface
-	ticketRepo repositories.TicketRepositoryInterface
+	// walletMu serialises balance, ticket list and profile writes, which
+	// always start from the stored user rather than the caller's copy
+	walletMu sync.Mutex
+	// seatMu guards seatLocks. Each movie's seats have their own lock so
+	// the check that seats are free and the change that takes them are one
+	// step, whether it comes from a request or a background job
+	seatMu       sync.Mutex
+	seatLocks    map[int]*sync.Mutex
+	userRepo     repositories.UserRepositoryInterface
+	movieRepo    repositories.MovieRepositoryInterface
+	ticketRepo   repositories.TicketRepositoryInterface
+	quoteRepo    repositories.QuoteRepositoryInterface
+	events       realtime.HubInterface
+	pricing      pricing.ServiceInterface
+	promos       promo.UseCaseInterface
+	loyalty      loyalty.UseCaseInterface
+	holdDuration time.Duration
+	maxSeats     int
+
+	// restrictedRating is the lowest age rating that also needs an ID
+	// checked by staff, zero disables the check
+	restrictedRating int
+	// presaleWindow is how long before a movie's release day tickets go
+	// on sale
+	presaleWindow time.Duration
 }
 
 type UseCaseInterface interface {
@@ -21,22 +67,74 @@ type UseCaseInterface interface {
 	BuyTicket(u entities.User, t entities.Ticket) error
 	CancelTicket(u entities.User, t entities.Ticket) error
 	NotPermitted(m entities.Movie, u entities.User) bool
+	SalesOpen(m entities.Movie) time.Time
+	OnSale(m entities.Movie, now time.Time) error
 	CheckBalance(u entities.User, p int) error
-	CheckAvailability(seat []entities.Seat, m *entities.Movie) ([]entities.Seat, error)
+	QuotePrice(m entities.Movie, seats []entities.Seat) entities.PriceBreakdown
+	LockQuote(u entities.User, m entities.Movie, seats []entities.Seat) (entities.PriceQuote, error)
+	UseQuote(id string, u entities.User, m entities.Movie, seats []entities.Seat) (entities.PriceBreakdown, error)
+	ApplyPromo(code string, u entities.User, m entities.Movie, b *entities.PriceBreakdown) error
+	RedeemPromo(u entities.User, m entities.Movie, ticketID string, b entities.PriceBreakdown) error
+	ReleasePromo(ticketID string) error
+	ApplyPoints(u entities.User, points int, b *entities.PriceBreakdown) error
+	RedeemPoints(u entities.User, ticketID string, b entities.PriceBreakdown) error
+	ReversePoints(ticketID string) error
+	CheckAvailability(seat []entities.Seat, m *entities.Movie, u entities.User) ([]entities.Seat, error)
+	SuggestSeats(m entities.Movie, u entities.User, opts seating.Options) ([]entities.Seat, error)
+	HoldSeats(seat []entities.Seat, m *entities.Movie, u entities.User) ([]entities.Seat, error)
+	HoldSeatsUntil(seat []entities.Seat, m *entities.Movie, u entities.User, until time.Time) ([]entities.Seat, error)
+	ReserveSeats(seat []entities.Seat, m *entities.Movie, holder string, until time.Time) ([]entities.Seat, error)
+	ReleaseReserved(seat []entities.Seat, m *entities.Movie, holder string) ([]entities.Seat, error)
+	ReleaseSeats(seat []entities.Seat, m *entities.Movie, u entities.User) ([]entities.Seat, error)
+	BookSeats(seat []entities.Seat, m *entities.Movie, holder string) ([]entities.Seat, error)
+	FreeSeats(seat []entities.Seat, m *entities.Movie) []entities.Seat
 	TopUp(u *entities.User, n int) error
 	Withdraw(u *entities.User, n int) error
+	MoveBalance(from, to string, n int) error
+	ReverseBalance(from, to string, n int) error
+	MoveTicket(ticketID, from, to string) (entities.Ticket, error)
+	CheckIn(ticketID, staff string) (entities.Ticket, error)
+	GetDependents(guardianID string) ([]entities.User, error)
+	UpdateUser(id string, update func(*entities.User) error) (entities.User, error)
+	AssignAttendees(u entities.User, m entities.Movie, seats []entities.Seat, profiles map[string]string) ([]entities.Attendee, error)
+	SetBirthdate(username string, birthdate time.Time) (entities.User, error)
+	VerifyID(username, staff string, birthdate time.Time) (entities.User, error)
+	Run(ctx context.Context, interval time.Duration)
 }
 
 func NewUseCase(userRepo repositories.UserRepositoryInterface,
 	movieRepo repositories.MovieRepositoryInterface,
-	ticketRepo repositories.TicketRepositoryInterface) UseCaseInterface {
+	ticketRepo repositories.TicketRepositoryInterface,
+	quoteRepo repositories.QuoteRepositoryInterface,
+	events realtime.HubInterface,
+	pricing pricing.ServiceInterface,
+	promos promo.UseCaseInterface,
+	loyalty loyalty.UseCaseInterface,
+	holdDuration time.Duration,
+	maxSeats int,
+	restrictedRating int,
+	presaleWindow time.Duration) UseCaseInterface {
 	return &useCase{
-		userRepo:   userRepo,
-		movieRepo:  movieRepo,
-		ticketRepo: ticketRepo,
+		userRepo:     userRepo,
+		movieRepo:    movieRepo,
+		ticketRepo:   ticketRepo,
+		quoteRepo:    quoteRepo,
+		events:       events,
+		pricing:      pricing,
+		promos:       promos,
+		loyalty:      loyalty,
+		holdDuration: holdDuration,
+		maxSeats:     maxSeats,
+		seatLocks:    make(map[int]*sync.Mutex),
+
+		restrictedRating: restrictedRating,
+		presaleWindow:    presaleWindow,
 	}
 }
 func (usecase *useCase) Create(user entities.User) error {
+	if err := validBirthdate(user.Birthdate); err != nil {
+		return err
+	}
 	if err := usecase.userRepo.Create(user); err != nil {
 		return err
 	}
@@ -67,38 +165,196 @@ func (usecase *useCase) GetUser(username string) (entities.User, error) {
 }
 
 func (usecase *useCase) BuyTicket(u entities.User, t entities.Ticket) error {
+	created := false
 	if err := usecase.ticketRepo.Update(t); err != nil {
 		if err := usecase.ticketRepo.Create(t); err != nil {
 			return err
 		}
+		created = true
 	}
-	u.Ticket = append(u.Ticket, t)
-	if err := usecase.userRepo.Update(u); err != nil {
+	usecase.walletMu.Lock()
+	current, err := usecase.userRepo.GetByUsername(u.Username)
+	if err == nil {
+		current.Ticket = append(current.Ticket, t)
+		err = usecase.userRepo.Update(current)
+	}
+	usecase.walletMu.Unlock()
+	if err != nil {
+		// Leave no ticket behind the caller is about to refund
+		if created {
+			usecase.ticketRepo.Delete(t)
+		}
 		return err
 	}
+	if t.Breakdown.QuoteID != "" {
+		usecase.quoteRepo.Delete(t.Breakdown.QuoteID)
+	}
+	if err := usecase.loyalty.Earn(u, t); err != nil {
+		slog.Error("UseCase.BuyTicket.01", "error", err, "ticket_id", t.ID)
+	}
+	usecase.events.Publish(realtime.SeatBooked, t.Movie.ID, t.Seats)
 	return nil
 }
 
+// NotPermitted reports whether the user is under the movie's age rating on
+// the day of the showtime in the local timezone, or has not had their ID
+// checked for a restricted rating
 func (usecase *useCase) NotPermitted(m entities.Movie, u entities.User) bool {
-	return m.Age_rating > u.Age
+	at := m.Showtime
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if m.Age_rating > u.AgeAt(at.In(time.Local)) {
+		return true
+	}
+	return usecase.restrictedRating > 0 && m.Age_rating >= usecase.restrictedRating && !u.ID_verified
+}
+
+// SalesOpen is when tickets for the movie go on sale, the presale window
+// ahead of its release day. It is zero for movies without a release date
+func (usecase *useCase) SalesOpen(m entities.Movie) time.Time {
+	if m.Release_date.IsZero() {
+		return time.Time{}
+	}
+	return m.Release_date.Add(-usecase.presaleWindow)
+}
+
+// OnSale checks that tickets for the movie are being sold
+func (usecase *useCase) OnSale(m entities.Movie, now time.Time) error {
+	if opens := usecase.SalesOpen(m); now.Before(opens) {
+		return fmt.Errorf("%w: tickets go on sale %s", ErrNotOnSale, opens.Format(time.RFC3339))
+	}
+	return nil
 }
 
 func (usecase *useCase) CheckBalance(u entities.User, p int) error {
 	if u.Balance < p {
 		return errors.New("BALANCE_INSUFFICIENT")
 	}
+	return spendable(u, p, time.Now())
+}
+func (usecase *useCase) QuotePrice(m entities.Movie, seats []entities.Seat) entities.PriceBreakdown {
+	return usecase.pricing.Quote(m, seats)
+}
+
+// LockQuote prices the seats the user just picked and keeps that price for
+// as long as the seats stay held
+func (usecase *useCase) LockQuote(u entities.User, m entities.Movie, seats []entities.Seat) (entities.PriceQuote, error) {
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		return entities.PriceQuote{}, err
+	}
+	now := time.Now()
+	usecase.quoteRepo.DeleteExpired(now)
+
+	breakdown := usecase.pricing.Quote(m, seats)
+	breakdown.QuoteID = UUID.String()
+	quote := entities.PriceQuote{
+		ID:         UUID.String(),
+		UserID:     u.ID,
+		MovieID:    m.ID,
+		Seats:      seatLabels(seats),
+		Breakdown:  breakdown,
+		Expires_at: now.Add(usecase.holdDuration),
+	}
+	if err := usecase.quoteRepo.Create(quote); err != nil {
+		return entities.PriceQuote{}, err
+	}
+	return quote, nil
+}
+
+// UseQuote returns the locked breakdown when the quote belongs to the user
+// and covers exactly the seats being bought
+func (usecase *useCase) UseQuote(id string, u entities.User, m entities.Movie, seats []entities.Seat) (entities.PriceBreakdown, error) {
+	quote, err := usecase.quoteRepo.Read(id)
+	if err != nil || quote.UserID != u.ID {
+		return entities.PriceBreakdown{}, errors.New("QUOTE_NOT_FOUND")
+	}
+	if time.Now().After(quote.Expires_at) {
+		return entities.PriceBreakdown{}, errors.New("QUOTE_EXPIRED")
+	}
+	labels := seatLabels(seats)
+	if quote.MovieID != m.ID || len(labels) != len(quote.Seats) {
+		return entities.PriceBreakdown{}, errors.New("QUOTE_MISMATCH")
+	}
+	for i := range labels {
+		if labels[i] != quote.Seats[i] {
+			return entities.PriceBreakdown{}, errors.New("QUOTE_MISMATCH")
+		}
+	}
+	return quote.Breakdown, nil
+}
+
+// ApplyPromo validates the code and adds its discount to the breakdown, the
+// code is only used up by RedeemPromo
+func (usecase *useCase) ApplyPromo(code string, u entities.User, m entities.Movie, b *entities.PriceBreakdown) error {
+	p, discount, err := usecase.promos.Evaluate(code, u, m, b.Subtotal)
+	if err != nil {
+		return err
+	}
+	b.Adjust(entities.PriceAdjustment{
+		Kind:        entities.AdjustmentPromo,
+		Code:        p.Code,
+		Description: promo.Describe(p),
+		Amount:      -discount,
+	})
 	return nil
 }
-func (usecase *useCase) CheckAvailability(seat []entities.Seat, m *entities.Movie) ([]entities.Seat, error) {
+
+func (usecase *useCase) RedeemPromo(u entities.User, m entities.Movie, ticketID string, b entities.PriceBreakdown) error {
+	for _, adjustment := range b.Adjustments {
+		if adjustment.Kind != entities.AdjustmentPromo {
+			continue
+		}
+		discount, err := usecase.promos.Redeem(adjustment.Code, u, m, ticketID, b.Subtotal)
+		if err != nil {
+			return err
+		}
+		if discount != -adjustment.Amount {
+			usecase.promos.Release(ticketID)
+			return errors.New("PROMO_CHANGED")
+		}
+	}
+	return nil
+}
+
+func (usecase *useCase) ReleasePromo(ticketID string) error {
+	return usecase.promos.Release(ticketID)
+}
+
+// ApplyPoints takes loyalty points off the breakdown total, the points are
+// only spent by RedeemPoints
+func (usecase *useCase) ApplyPoints(u entities.User, points int, b *entities.PriceBreakdown) error {
+	return usecase.loyalty.Quote(u, points, b)
+}
+
+func (usecase *useCase) RedeemPoints(u entities.User, ticketID string, b entities.PriceBreakdown) error {
+	return usecase.loyalty.Redeem(u, ticketID, b)
+}
+
+func (usecase *useCase) ReversePoints(ticketID string) error {
+	return usecase.loyalty.Reverse(ticketID)
+}
+
+func (usecase *useCase) CheckAvailability(seat []entities.Seat, m *entities.Movie, u entities.User) ([]entities.Seat, error) {
+	if len(seat) > usecase.maxSeats {
+		return nil, errors.New("MAX_TICKET_REACH")
+	}
+	unlock := usecase.lockSeats(m)
+	defer unlock()
+	return usecase.available(seat, m, u.Username)
+}
+
+// available looks up the requested seats in the movie, failing unless every
+// one of them is free for the holder. Callers hold the movie's seat lock
+func (usecase *useCase) available(seat []entities.Seat, m *entities.Movie, holder string) ([]entities.Seat, error) {
 	var (
 		// countTicket int
 		seats    []entities.Seat
 		err      error
 		prevSeat *entities.Seat
 	)
-	if len(seat) > 6 {
-		return nil, errors.New("MAX_TICKET_REACH")
-	}
+	now := time.Now()
 
 	for _, s := range seat {
 		var foundSeat *entities.Seat
@@ -110,7 +366,7 @@ func (usecase *useCase) CheckAvailability(seat []entities.Seat, m *entities.Movi
 			}
 		}
 
-		if foundSeat == nil || foundSeat.Booked {
+		if foundSeat == nil || !seatFree(*foundSeat, holder, now) {
 			err = errors.New("PANIC")
 			// countTicket = 0
 			seats = nil
@@ -124,7 +380,7 @@ func (usecase *useCase) CheckAvailability(seat []entities.Seat, m *entities.Movi
 		prevSeat = foundSeat
 		// countTicket++
 		seats = append(seats, *foundSeat)
-		log.Printf("Booked seat: %v", foundSeat)
+		slog.Debug("Booked seat", "row", foundSeat.Row, "number", foundSeat.Number)
 	}
 	if len(seat) > len(seats) || seats == nil {
 		err = errors.New("TICKET_UNAVAILABLE")
@@ -136,10 +392,18 @@ func (usecase *useCase) TopUp(u *entities.User, n int) error {
 	if n < 0 {
 		return errors.New("NEGATIVE_VALUE")
 	}
-	u.Balance += n
-	if err := usecase.userRepo.Update(*u); err != nil {
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	current, err := usecase.userRepo.GetByUsername(u.Username)
+	if err != nil {
 		return err
 	}
+	current.Balance += n
+	if err := usecase.userRepo.Update(current); err != nil {
+		return err
+	}
+	u.Balance = current.Balance
 	return nil
 }
 
@@ -147,29 +411,668 @@ func (usecase *useCase) Withdraw(u *entities.User, n int) error {
 	if n < 0 {
 		return errors.New("NEGATIVE_VALUE")
 	}
-	if n > u.Balance {
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	current, err := usecase.userRepo.GetByUsername(u.Username)
+	if err != nil {
+		return err
+	}
+	if n > current.Balance {
 		return errors.New("BALANCE_INSUFFICIENT")
 	}
-	u.Balance -= n
-	if err := usecase.userRepo.Update(*u); err != nil {
+	if err := spend(&current, n, time.Now()); err != nil {
 		return err
 	}
+	current.Balance -= n
+	if err := usecase.userRepo.Update(current); err != nil {
+		return err
+	}
+	u.Balance = current.Balance
 	return nil
 }
 
-func (usecase *useCase) CancelTicket(u entities.User, t entities.Ticket) error {
-	for i, t2 := range u.Ticket {
-		if t.ID == t2.ID {
-			u.Ticket = append(u.Ticket[:i], u.Ticket[i+1:]...)
-			break
+// MoveBalance debits one user and credits another as a single step, either
+// both writes happen or neither does
+func (usecase *useCase) MoveBalance(from, to string, n int) error {
+	if n <= 0 {
+		return errors.New("NON_POSITIVE_VALUE")
+	}
+	if from == to {
+		return errors.New("SAME_ACCOUNT")
+	}
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	sender, err := usecase.userRepo.GetByUsername(from)
+	if err != nil {
+		return err
+	}
+	recipient, err := usecase.userRepo.GetByUsername(to)
+	if err != nil {
+		return err
+	}
+	if n > sender.Balance {
+		return errors.New("BALANCE_INSUFFICIENT")
+	}
+	before := sender
+	if err := spend(&sender, n, time.Now()); err != nil {
+		return err
+	}
+	sender.Balance -= n
+	recipient.Balance += n
+	if err := usecase.userRepo.Update(sender); err != nil {
+		return err
+	}
+	if err := usecase.userRepo.Update(recipient); err != nil {
+		usecase.userRepo.Update(before)
+		return err
+	}
+	return nil
+}
+
+// ReverseBalance undoes a MoveBalance of n from one user to another. The
+// recipient's spending limit does not apply, the money was never theirs to
+// spend, and the sender's monthly spending drops back
+func (usecase *useCase) ReverseBalance(from, to string, n int) error {
+	if n <= 0 {
+		return errors.New("NON_POSITIVE_VALUE")
+	}
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	sender, err := usecase.userRepo.GetByUsername(from)
+	if err != nil {
+		return err
+	}
+	recipient, err := usecase.userRepo.GetByUsername(to)
+	if err != nil {
+		return err
+	}
+	if n > recipient.Balance {
+		return errors.New("BALANCE_INSUFFICIENT")
+	}
+	before := recipient
+	recipient.Balance -= n
+	sender.Balance += n
+	if sender.Spent_month == time.Now().Format("2006-01") {
+		sender.Spent = max(0, sender.Spent-n)
+	}
+	if err := usecase.userRepo.Update(recipient); err != nil {
+		return err
+	}
+	if err := usecase.userRepo.Update(sender); err != nil {
+		usecase.userRepo.Update(before)
+		return err
+	}
+	return nil
+}
+
+// MoveTicket hands a ticket from one user to another, moving it between
+// their ticket lists and recording the sender as a previous owner. The
+// sender's attendees do not go with it, the recipient sits in every seat
+func (usecase *useCase) MoveTicket(ticketID, from, to string) (entities.Ticket, error) {
+	if from == to {
+		return entities.Ticket{}, errors.New("SAME_ACCOUNT")
+	}
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	sender, err := usecase.userRepo.GetByUsername(from)
+	if err != nil {
+		return entities.Ticket{}, err
+	}
+	recipient, err := usecase.userRepo.GetByUsername(to)
+	if err != nil {
+		return entities.Ticket{}, err
+	}
+	ticket, err := usecase.ticketRepo.Read(ticketID)
+	if err != nil || ticket.UserID != sender.ID {
+		return entities.Ticket{}, errors.New("TICKET_NOT_OWNED")
+	}
+
+	previous := ticket
+	ticket.UserID = recipient.ID
+	ticket.Attendees = nil
+	ticket.PreviousOwners = append(append([]entities.TicketOwner(nil), ticket.PreviousOwners...), entities.TicketOwner{
+		UserID:         sender.ID,
+		Username:       sender.Username,
+		Transferred_at: time.Now(),
+	})
+	if err := usecase.ticketRepo.Update(ticket); err != nil {
+		return entities.Ticket{}, err
+	}
+
+	tickets := make([]entities.Ticket, 0, len(sender.Ticket))
+	for _, t := range sender.Ticket {
+		if t.ID != ticket.ID {
+			tickets = append(tickets, t)
+		}
+	}
+	sender.Ticket = tickets
+	recipient.Ticket = append(recipient.Ticket, ticket)
+	if err := usecase.userRepo.Update(sender); err != nil {
+		usecase.ticketRepo.Update(previous)
+		return entities.Ticket{}, err
+	}
+	if err := usecase.userRepo.Update(recipient); err != nil {
+		sender.Ticket = append(sender.Ticket, previous)
+		usecase.userRepo.Update(sender)
+		usecase.ticketRepo.Update(previous)
+		return entities.Ticket{}, err
+	}
+	return ticket, nil
+}
+
+// CheckIn marks a ticket used, on the stored ticket and in its owner's list
+func (usecase *useCase) CheckIn(ticketID, staff string) (entities.Ticket, error) {
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	ticket, err := usecase.ticketRepo.Read(ticketID)
+	if err != nil {
+		return entities.Ticket{}, err
+	}
+	if ticket.CheckedIn() {
+		return ticket, errors.New("ALREADY_CHECKED_IN")
+	}
+	owner, err := usecase.userRepo.Read(ticket.UserID)
+	if err != nil {
+		return entities.Ticket{}, err
+	}
+
+	ticket.CheckedInAt = time.Now()
+	ticket.CheckedInBy = staff
+	if err := usecase.ticketRepo.Update(ticket); err != nil {
+		return entities.Ticket{}, err
+	}
+	tickets := make([]entities.Ticket, len(owner.Ticket))
+	copy(tickets, owner.Ticket)
+	for i := range tickets {
+		if tickets[i].ID == ticket.ID {
+			tickets[i] = ticket
 		}
 	}
+	owner.Ticket = tickets
+	if err := usecase.userRepo.Update(owner); err != nil {
+		return entities.Ticket{}, err
+	}
+	return ticket, nil
+}
 
-	if err := usecase.userRepo.Update(u); err != nil {
+// CancelTicket removes a ticket whose show has not started. Its seats are
+// only freed while the movie is still on the ticket's screening, after the
+// daily roll-over the same seats belong to the next screening's buyers
+func (usecase *useCase) CancelTicket(u entities.User, t entities.Ticket) error {
+	if !t.Movie.Showtime.IsZero() && !time.Now().Before(t.Movie.Showtime) {
+		return ErrShowStarted
+	}
+	usecase.walletMu.Lock()
+	current, err := usecase.userRepo.GetByUsername(u.Username)
+	if err != nil {
+		usecase.walletMu.Unlock()
+		return err
+	}
+	tickets := make([]entities.Ticket, 0, len(current.Ticket))
+	for _, t2 := range current.Ticket {
+		if t.ID != t2.ID {
+			tickets = append(tickets, t2)
+		}
+	}
+	current.Ticket = tickets
+	err = usecase.userRepo.Update(current)
+	usecase.walletMu.Unlock()
+	if err != nil {
 		return err
 	}
 	if err := usecase.ticketRepo.Delete(t); err != nil {
 		return err
 	}
+	if movie, err := usecase.movieRepo.Read(t.Movie.ID); err == nil && movie.Showtime.Equal(t.Movie.Showtime) {
+		usecase.FreeSeats(t.Seats, &movie)
+	}
+	if err := usecase.promos.Release(t.ID); err != nil {
+		return err
+	}
+	if err := usecase.loyalty.Reverse(t.ID); err != nil {
+		return err
+	}
 	return nil
 }
+
+// SuggestSeats picks the best free seats for the user, counting seats the
+// user already holds as free
+func (usecase *useCase) SuggestSeats(m entities.Movie, u entities.User, opts seating.Options) ([]entities.Seat, error) {
+	unlock := usecase.lockSeats(&m)
+	defer unlock()
+
+	now := time.Now()
+	opts.MaxPartySize = usecase.maxSeats
+	suggestion, err := seating.Suggest(m.Seats, opts, func(s entities.Seat) bool {
+		return seatFree(s, u.Username, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return suggestion.Seats, nil
+}
+
+// HoldSeats reserves seats for the user while they check out, a hold lapses
+// on its own after the configured hold duration
+func (usecase *useCase) HoldSeats(seat []entities.Seat, m *entities.Movie, u entities.User) ([]entities.Seat, error) {
+	return usecase.HoldSeatsUntil(seat, m, u, time.Now().Add(usecase.holdDuration))
+}
+
+// HoldSeatsUntil holds seats for the user until the given time instead of
+// the configured hold duration
+func (usecase *useCase) HoldSeatsUntil(seat []entities.Seat, m *entities.Movie, u entities.User, until time.Time) ([]entities.Seat, error) {
+	if len(seat) > usecase.maxSeats {
+		return nil, errors.New("MAX_TICKET_REACH")
+	}
+	return usecase.ReserveSeats(seat, m, u.Username, until)
+}
+
+// ReserveSeats holds a block of seats for a holder that is not a user,
+// such as a group booking. The per-order seat limit does not apply
+func (usecase *useCase) ReserveSeats(seat []entities.Seat, m *entities.Movie, holder string, until time.Time) ([]entities.Seat, error) {
+	unlock := usecase.lockSeats(m)
+	defer unlock()
+
+	seats, err := usecase.available(seat, m, holder)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.hold(seats, m, holder, until)
+}
+
+// ReleaseSeats drops the user's holds on the given seats
+func (usecase *useCase) ReleaseSeats(seat []entities.Seat, m *entities.Movie, u entities.User) ([]entities.Seat, error) {
+	return usecase.ReleaseReserved(seat, m, u.Username)
+}
+
+// ReleaseReserved drops the holder's holds on the given seats
+func (usecase *useCase) ReleaseReserved(seat []entities.Seat, m *entities.Movie, holder string) ([]entities.Seat, error) {
+	unlock := usecase.lockSeats(m)
+	defer unlock()
+
+	var released []entities.Seat
+	now := time.Now()
+	for _, s := range seat {
+		for i := range m.Seats {
+			if s.Row != m.Seats[i].Row || s.Number != m.Seats[i].Number {
+				continue
+			}
+			if m.Seats[i].HeldBy == holder && m.Seats[i].Status(now) == entities.SeatHeld {
+				m.Seats[i].HeldBy = ""
+				m.Seats[i].HeldUntil = time.Time{}
+				released = append(released, m.Seats[i])
+			}
+		}
+	}
+	if len(released) == 0 {
+		return nil, errors.New("NOT_HELD")
+	}
+	if err := usecase.saveSeats(m); err != nil {
+		return nil, err
+	}
+	usecase.events.Publish(realtime.SeatReleased, m.ID, released)
+	return released, nil
+}
+
+// BookSeats marks the seats booked if every one is still free for the
+// holder, dropping the holder's holds on them
+func (usecase *useCase) BookSeats(seat []entities.Seat, m *entities.Movie, holder string) ([]entities.Seat, error) {
+	unlock := usecase.lockSeats(m)
+	defer unlock()
+
+	seats, err := usecase.available(seat, m, holder)
+	if err != nil {
+		return nil, err
+	}
+	for i1, s1 := range seats {
+		for i2 := range m.Seats {
+			if s1.Row == m.Seats[i2].Row && s1.Number == m.Seats[i2].Number {
+				m.Seats[i2].Booked = true
+				m.Seats[i2].HeldBy = ""
+				m.Seats[i2].HeldUntil = time.Time{}
+				seats[i1] = m.Seats[i2]
+			}
+		}
+	}
+	if err := usecase.saveSeats(m); err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
+
+// FreeSeats makes booked seats available again, as when a purchase is
+// undone or a ticket cancelled
+func (usecase *useCase) FreeSeats(seat []entities.Seat, m *entities.Movie) []entities.Seat {
+	unlock := usecase.lockSeats(m)
+	defer unlock()
+
+	var freed []entities.Seat
+	for _, s := range seat {
+		for i := range m.Seats {
+			if s.Row == m.Seats[i].Row && s.Number == m.Seats[i].Number && m.Seats[i].Booked {
+				m.Seats[i].Booked = false
+				freed = append(freed, m.Seats[i])
+			}
+		}
+	}
+	if err := usecase.saveSeats(m); err != nil {
+		slog.Error("UseCase.FreeSeats.01", "error", err, "movie_id", m.ID)
+		return nil
+	}
+	usecase.events.Publish(realtime.SeatReleased, m.ID, freed)
+	return freed
+}
+
+// hold marks seats found by available as held. Callers hold the movie's
+// seat lock
+func (usecase *useCase) hold(seats []entities.Seat, m *entities.Movie, holder string, until time.Time) ([]entities.Seat, error) {
+	for i1, s1 := range seats {
+		for i2 := range m.Seats {
+			if s1.Row == m.Seats[i2].Row && s1.Number == m.Seats[i2].Number {
+				m.Seats[i2].HeldBy = holder
+				m.Seats[i2].HeldUntil = until
+				seats[i1] = m.Seats[i2]
+			}
+		}
+	}
+	if err := usecase.saveSeats(m); err != nil {
+		return nil, err
+	}
+	usecase.events.Publish(realtime.SeatHeld, m.ID, seats)
+	return seats, nil
+}
+
+// Run clears lapsed seat holds and moves shows that started on to the next
+// day every interval until the context is cancelled, so seat map clients
+// hear that the seats are free again
+func (usecase *useCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := usecase.sweep(now); err != nil {
+				slog.Error("UseCase.User.Run.01", "error", err)
+			}
+		}
+	}
+}
+
+// sweep drops holds that lapsed and starts the next screening of movies
+// whose show started, publishing the seats that became free
+func (usecase *useCase) sweep(now time.Time) error {
+	movies, err := usecase.movieRepo.ReadAll()
+	if err != nil {
+		return err
+	}
+	for _, m := range movies {
+		unlock := usecase.lockSeats(&m)
+		var freed []entities.Seat
+		if !m.Showtime.IsZero() && !now.Before(m.Showtime) {
+			freed = usecase.nextScreening(m.ID, now)
+		} else {
+			for i := range m.Seats {
+				if m.Seats[i].HeldBy != "" && !now.Before(m.Seats[i].HeldUntil) {
+					m.Seats[i].HeldBy = ""
+					m.Seats[i].HeldUntil = time.Time{}
+					freed = append(freed, m.Seats[i])
+				}
+			}
+			if len(freed) > 0 {
+				if err := usecase.saveSeats(&m); err != nil {
+					slog.Error("UseCase.User.Sweep.01", "error", err, "movie_id", m.ID)
+					freed = nil
+				}
+			}
+		}
+		unlock()
+		usecase.events.Publish(realtime.SeatReleased, m.ID, freed)
+	}
+	return nil
+}
+
+// nextScreening moves the movie to the same time on the first day whose
+// show has not started, with every seat free again. The seats are a new
+// copy, tickets for the past show keep theirs. Callers hold the movie's
+// seat lock
+func (usecase *useCase) nextScreening(movieID int, now time.Time) []entities.Seat {
+	movie, err := usecase.movieRepo.Read(movieID)
+	if err != nil {
+		return nil
+	}
+	showtime := movie.Showtime
+	for !now.Before(showtime) {
+		showtime = showtime.AddDate(0, 0, 1)
+	}
+	var freed []entities.Seat
+	seats := make([]entities.Seat, len(movie.Seats))
+	for i, seat := range movie.Seats {
+		if seat.Booked || seat.HeldBy != "" {
+			freed = append(freed, seat)
+		}
+		seat.Booked = false
+		seat.HeldBy = ""
+		seat.HeldUntil = time.Time{}
+		seats[i] = seat
+	}
+	if err := usecase.movieRepo.UpdateScreening(movieID, showtime, seats); err != nil {
+		slog.Error("UseCase.User.NextScreening.01", "error", err, "movie_id", movieID)
+		return nil
+	}
+	return freed
+}
+
+// lockSeats takes the movie's seat lock and returns its unlock. The movie
+// is brought up to date first, it may have moved on to its next screening
+// since the caller read it
+func (usecase *useCase) lockSeats(m *entities.Movie) func() {
+	usecase.seatMu.Lock()
+	l, ok := usecase.seatLocks[m.ID]
+	if !ok {
+		l = &sync.Mutex{}
+		usecase.seatLocks[m.ID] = l
+	}
+	usecase.seatMu.Unlock()
+
+	l.Lock()
+	if current, err := usecase.movieRepo.Read(m.ID); err == nil {
+		m.Showtime = current.Showtime
+		m.Seats = current.Seats
+	}
+	return l.Unlock
+}
+
+// saveSeats stores the seats of a movie changed under its seat lock, the
+// movie repository hands out copies
+func (usecase *useCase) saveSeats(m *entities.Movie) error {
+	return usecase.movieRepo.UpdateScreening(m.ID, m.Showtime, m.Seats)
+}
+
+// GetDependents lists the profiles managed by a guardian
+func (usecase *useCase) GetDependents(guardianID string) ([]entities.User, error) {
+	return usecase.userRepo.ReadByGuardian(guardianID)
+}
+
+// UpdateUser applies update to the stored user, nothing is written when it
+// fails
+func (usecase *useCase) UpdateUser(id string, update func(*entities.User) error) (entities.User, error) {
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	current, err := usecase.userRepo.Read(id)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if err := update(&current); err != nil {
+		return entities.User{}, err
+	}
+	current.Updated_at = time.Now()
+	if err := usecase.userRepo.Update(current); err != nil {
+		return entities.User{}, err
+	}
+	return current, nil
+}
+
+// AssignAttendees seats the purchaser or one of their dependents in each
+// seat, profiles maps seat labels to profile IDs and unassigned seats go to
+// the purchaser. Every attendee must meet the movie's age rating
+func (usecase *useCase) AssignAttendees(u entities.User, m entities.Movie, seats []entities.Seat, profiles map[string]string) ([]entities.Attendee, error) {
+	members := map[string]entities.User{u.ID: u}
+	dependents, err := usecase.userRepo.ReadByGuardian(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dependent := range dependents {
+		members[dependent.ID] = dependent
+	}
+
+	labels := make(map[string]bool, len(seats))
+	attendees := make([]entities.Attendee, 0, len(seats))
+	for _, seat := range seats {
+		label := fmt.Sprintf("%s%d", seat.Row, seat.Number)
+		labels[label] = true
+		profileID, ok := profiles[label]
+		if !ok {
+			profileID = u.ID
+		}
+		member, ok := members[profileID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAttendee, profileID)
+		}
+		if usecase.NotPermitted(m, member) {
+			return nil, fmt.Errorf("%w: %s", ErrAgeRestriction, member.Name)
+		}
+		attendees = append(attendees, entities.Attendee{
+			Seat:      label,
+			ProfileID: member.ID,
+			Name:      member.Name,
+		})
+	}
+	for label := range profiles {
+		if !labels[label] {
+			return nil, fmt.Errorf("%w: seat %s is not in the order", ErrInvalidAttendee, label)
+		}
+	}
+	return attendees, nil
+}
+
+// SetBirthdate records the user's date of birth, which is how accounts
+// registered with a typed-in age move over. Once staff have verified an ID
+// only they can change it
+func (usecase *useCase) SetBirthdate(username string, birthdate time.Time) (entities.User, error) {
+	if err := validBirthdate(birthdate); err != nil {
+		return entities.User{}, err
+	}
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	current, err := usecase.userRepo.GetByUsername(username)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if current.ID_verified {
+		return entities.User{}, ErrBirthdateLocked
+	}
+	current.Birthdate = birthdate
+	current.Age = 0
+	current.Updated_at = time.Now()
+	if err := usecase.userRepo.Update(current); err != nil {
+		return entities.User{}, err
+	}
+	return current, nil
+}
+
+// VerifyID marks the user's ID as checked by staff, recording the date of
+// birth shown on it
+func (usecase *useCase) VerifyID(username, staff string, birthdate time.Time) (entities.User, error) {
+	if err := validBirthdate(birthdate); err != nil {
+		return entities.User{}, err
+	}
+	usecase.walletMu.Lock()
+	defer usecase.walletMu.Unlock()
+
+	current, err := usecase.userRepo.GetByUsername(username)
+	if err != nil {
+		return entities.User{}, err
+	}
+	now := time.Now()
+	current.Birthdate = birthdate
+	current.Age = 0
+	current.ID_verified = true
+	current.ID_verified_by = staff
+	current.ID_verified_at = now
+	current.Updated_at = now
+	if err := usecase.userRepo.Update(current); err != nil {
+		return entities.User{}, err
+	}
+	return current, nil
+}
+
+// spendable checks a payment against a dependent's monthly spending limit
+func spendable(u entities.User, n int, now time.Time) error {
+	if u.Spending_limit <= 0 {
+		return nil
+	}
+	spent := u.Spent
+	if u.Spent_month != now.Format("2006-01") {
+		spent = 0
+	}
+	if spent+n > u.Spending_limit {
+		return ErrSpendingLimit
+	}
+	return nil
+}
+
+// spend counts a payment towards the user's monthly spending
+func spend(u *entities.User, n int, now time.Time) error {
+	if err := spendable(*u, n, now); err != nil {
+		return err
+	}
+	if !u.Dependent() {
+		return nil
+	}
+	month := now.Format("2006-01")
+	if u.Spent_month != month {
+		u.Spent_month = month
+		u.Spent = 0
+	}
+	u.Spent += n
+	return nil
+}
+
+func validBirthdate(birthdate time.Time) error {
+	now := time.Now()
+	if birthdate.IsZero() || birthdate.After(now) || now.Year()-birthdate.Year() > maxAge {
+		return ErrInvalidBirthdate
+	}
+	return nil
+}
+
+func seatLabels(seats []entities.Seat) []string {
+	labels := make([]string, 0, len(seats))
+	for _, seat := range seats {
+		labels = append(labels, fmt.Sprintf("%s%d", seat.Row, seat.Number))
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// seatFree reports whether the holder may take the seat, either because
+// nobody has it or because the holder holds it
+func seatFree(s entities.Seat, holder string, at time.Time) bool {
+	switch s.Status(at) {
+	case entities.SeatAvailable:
+		return true
+	case entities.SeatHeld:
+		return s.HeldBy == holder
+	}
+	return false
+}
@@ -0,0 +1,301 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/loyalty"
+	"movie-app-go/modules/pricing"
+	"movie-app-go/modules/promo"
+	"movie-app-go/modules/realtime"
+	"movie-app-go/repositories"
+)
+
+const movieID = 1
+
+type fixture struct {
+	usecase   *useCase
+	movieRepo repositories.MovieRepositoryInterface
+}
+
+// newFixture sets up one movie showing at showtime and the users alice and
+// bob with enough balance for a few tickets
+func newFixture(t *testing.T, showtime time.Time) fixture {
+	t.Helper()
+	movieRepo := repositories.NewMovieRepository([]entities.Movie{
+		{ID: movieID, Title: "Fixture", Showtime: showtime, Seats: repositories.GenerateSeats()},
+	})
+	userRepo := repositories.NewUserRepository([]entities.User{
+		{ID: "u-alice", Username: "alice", Balance: 100000},
+		{ID: "u-bob", Username: "bob", Balance: 100000},
+	})
+	usecase := NewUseCase(userRepo, movieRepo,
+		repositories.NewTicketRepository(nil),
+		repositories.NewQuoteRepository(nil),
+		realtime.NewHub(0),
+		pricing.NewService(nil, nil, 1, 1),
+		promo.NewUseCase(repositories.NewPromoRepository(nil)),
+		loyalty.NewUseCase(repositories.NewLoyaltyRepository(nil), loyalty.Program{}),
+		time.Minute, 4, 0, 0,
+	).(*useCase)
+	return fixture{usecase: usecase, movieRepo: movieRepo}
+}
+
+// buy books the seats for the user and stores their ticket
+func (f fixture) buy(t *testing.T, username string, seats ...entities.Seat) entities.Ticket {
+	t.Helper()
+	u, err := f.usecase.GetUser(username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	movie, err := f.usecase.GetMovie(movieID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	booked, err := f.usecase.BookSeats(seats, &movie, username)
+	if err != nil {
+		t.Fatalf("booking %v for %s: %v", seats, username, err)
+	}
+	ticket := entities.Ticket{ID: "t-" + username, Movie: movie, UserID: u.ID, Seats: booked, Cost: 1000}
+	if err := f.usecase.BuyTicket(u, ticket); err != nil {
+		t.Fatal(err)
+	}
+	return ticket
+}
+
+func (f fixture) seat(t *testing.T, row string, number int) entities.Seat {
+	t.Helper()
+	movie, err := f.movieRepo.Read(movieID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, seat := range movie.Seats {
+		if seat.Row == row && seat.Number == number {
+			return seat
+		}
+	}
+	t.Fatalf("no seat %s%d", row, number)
+	return entities.Seat{}
+}
+
+// screen moves the movie to another screening with every seat free
+func (f fixture) screen(t *testing.T, showtime time.Time) {
+	t.Helper()
+	movie, err := f.movieRepo.Read(movieID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.movieRepo.UpdateScreening(movie.ID, showtime, repositories.GenerateSeats()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var a1 = entities.Seat{Row: "A", Number: 1}
+
+func TestSweepStartsNextScreening(t *testing.T) {
+	now := time.Now()
+	started := now.Add(-time.Hour)
+	f := newFixture(t, started)
+	f.buy(t, "alice", a1)
+	hold := entities.Seat{Row: "B", Number: 2}
+	movie, _ := f.usecase.GetMovie(movieID)
+	if _, err := f.usecase.ReserveSeats([]entities.Seat{hold}, &movie, "bob", now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.usecase.sweep(now); err != nil {
+		t.Fatal(err)
+	}
+
+	movie, _ = f.movieRepo.Read(movieID)
+	if want := started.AddDate(0, 0, 1); !movie.Showtime.Equal(want) {
+		t.Errorf("showtime = %v, want %v", movie.Showtime, want)
+	}
+	for _, seat := range []entities.Seat{a1, hold} {
+		if got := f.seat(t, seat.Row, seat.Number).Status(now); got != entities.SeatAvailable {
+			t.Errorf("seat %s%d is %s on the next screening, want available", seat.Row, seat.Number, got)
+		}
+	}
+	// Tickets keep the screening they were bought for
+	alice, _ := f.usecase.GetUser("alice")
+	if len(alice.Ticket) != 1 || !alice.Ticket[0].Movie.Showtime.Equal(started) || !alice.Ticket[0].Seats[0].Booked {
+		t.Errorf("alice's ticket changed with the roll-over: %+v", alice.Ticket)
+	}
+}
+
+func TestSweepClearsLapsedHolds(t *testing.T) {
+	now := time.Now()
+	f := newFixture(t, now.Add(time.Hour))
+	movie, _ := f.usecase.GetMovie(movieID)
+	lapsed, kept := entities.Seat{Row: "C", Number: 3}, entities.Seat{Row: "C", Number: 4}
+	f.usecase.ReserveSeats([]entities.Seat{lapsed}, &movie, "alice", now.Add(-time.Second))
+	f.usecase.ReserveSeats([]entities.Seat{kept}, &movie, "bob", now.Add(time.Minute))
+
+	if err := f.usecase.sweep(now); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.seat(t, "C", 3); got.HeldBy != "" {
+		t.Errorf("lapsed hold kept by %q", got.HeldBy)
+	}
+	if got := f.seat(t, "C", 4); got.HeldBy != "bob" {
+		t.Errorf("live hold dropped, held by %q", got.HeldBy)
+	}
+	if movie, _ := f.movieRepo.Read(movieID); !movie.Showtime.After(now) {
+		t.Errorf("upcoming show rolled over to %v", movie.Showtime)
+	}
+}
+
+func TestCancelTicket(t *testing.T) {
+	tests := []struct {
+		name string
+		// showAt is when the show starts, relative to now, as alice buys A1
+		showAt time.Duration
+		// before runs between alice buying and cancelling
+		before     func(t *testing.T, f fixture, now time.Time)
+		wantErr    error
+		wantStatus string
+	}{
+		{
+			name:       "upcoming show frees the seats",
+			showAt:     time.Hour,
+			wantStatus: entities.SeatAvailable,
+		},
+		{
+			name:       "started show is refused",
+			showAt:     -time.Minute,
+			wantErr:    ErrShowStarted,
+			wantStatus: entities.SeatBooked,
+		},
+		{
+			name:   "past screening leaves the next screening's buyers alone",
+			showAt: -time.Minute,
+			before: func(t *testing.T, f fixture, now time.Time) {
+				if err := f.usecase.sweep(now); err != nil {
+					t.Fatal(err)
+				}
+				f.buy(t, "bob", a1)
+			},
+			wantErr:    ErrShowStarted,
+			wantStatus: entities.SeatBooked,
+		},
+		{
+			name:   "another screening's seats are not freed",
+			showAt: time.Hour,
+			before: func(t *testing.T, f fixture, now time.Time) {
+				// The movie moved on while the ticket's show is still ahead
+				f.screen(t, now.Add(25*time.Hour))
+				f.buy(t, "bob", a1)
+			},
+			wantStatus: entities.SeatBooked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			f := newFixture(t, now.Add(tt.showAt))
+			ticket := f.buy(t, "alice", a1)
+			if tt.before != nil {
+				tt.before(t, f, now)
+			}
+
+			alice, _ := f.usecase.GetUser("alice")
+			if err := f.usecase.CancelTicket(alice, ticket); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := f.seat(t, "A", 1).Status(now); got != tt.wantStatus {
+				t.Errorf("A1 is %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// seatOp tries to take A1 for the holder
+type seatOp func(f fixture, holder string, m *entities.Movie) error
+
+func TestSeatLockTakesEachSeatOnce(t *testing.T) {
+	var book seatOp = func(f fixture, holder string, m *entities.Movie) error {
+		_, err := f.usecase.BookSeats([]entities.Seat{a1}, m, holder)
+		return err
+	}
+	var hold seatOp = func(f fixture, holder string, m *entities.Movie) error {
+		_, err := f.usecase.ReserveSeats([]entities.Seat{a1}, m, holder, time.Now().Add(time.Minute))
+		return err
+	}
+	tests := []struct {
+		name string
+		ops  []seatOp
+	}{
+		{name: "bookings", ops: []seatOp{book}},
+		{name: "holds", ops: []seatOp{hold}},
+		{name: "holds and bookings", ops: []seatOp{hold, book}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, time.Now().Add(time.Hour))
+			const buyers = 32
+
+			var (
+				wg      sync.WaitGroup
+				mu      sync.Mutex
+				winners []string
+			)
+			stop := make(chan struct{})
+			sweeping := make(chan struct{})
+			// The background sweep works on the same seats meanwhile
+			go func() {
+				defer close(sweeping)
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						f.usecase.sweep(time.Now())
+					}
+				}
+			}()
+			for i := 0; i < buyers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					holder := fmt.Sprintf("buyer-%d", i)
+					// Each buyer starts from the copy they read earlier
+					movie, _ := f.usecase.GetMovie(movieID)
+					if err := tt.ops[i%len(tt.ops)](f, holder, &movie); err == nil {
+						mu.Lock()
+						winners = append(winners, holder)
+						mu.Unlock()
+					}
+				}(i)
+			}
+			wg.Wait()
+			close(stop)
+			<-sweeping
+
+			if len(winners) != 1 {
+				t.Fatalf("%d buyers took A1: %v", len(winners), winners)
+			}
+			seat := f.seat(t, "A", 1)
+			if !seat.Booked && seat.HeldBy != winners[0] {
+				t.Errorf("A1 = %+v, want it taken by %s", seat, winners[0])
+			}
+		})
+	}
+}
+
+func TestBuyTicketLeavesNothingBehindOnFailure(t *testing.T) {
+	f := newFixture(t, time.Now().Add(time.Hour))
+	movie, _ := f.usecase.GetMovie(movieID)
+	ticket := entities.Ticket{ID: "t-carol", Movie: movie, UserID: "u-carol", Cost: 1000}
+
+	// carol has no account, so storing the ticket with her fails
+	if err := f.usecase.BuyTicket(entities.User{ID: "u-carol", Username: "carol"}, ticket); err == nil {
+		t.Fatal("buying for an unknown user succeeded")
+	}
+	if _, err := f.usecase.GetTicket(ticket.ID); err == nil {
+		t.Error("the failed purchase left its ticket stored")
+	}
+}
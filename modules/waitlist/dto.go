@@ -0,0 +1,13 @@
+package waitlist
+
+type (
+	JoinRequest struct {
+		PartySize int `json:"party_size" binding:"required,min=1"`
+	}
+	Response struct {
+		Code      int    `json:"code" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		Data      any    `json:"data" binding:"required"`
+		RequestID string `json:"request_id,omitempty"`
+	}
+)
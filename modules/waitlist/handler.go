@@ -0,0 +1,161 @@
+package waitlist
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+	"movie-app-go/modules/seating"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	waitlistUseCase UseCaseInterface
+	booking         Booking
+}
+
+type HandlerInterface interface {
+	JoinWaitlist(c *gin.Context)
+	GetWaitlist(c *gin.Context)
+	LeaveWaitlist(c *gin.Context)
+}
+
+func NewHandler(waitlistUseCase UseCaseInterface, booking Booking) HandlerInterface {
+	return &handler{
+		waitlistUseCase: waitlistUseCase,
+		booking:         booking,
+	}
+}
+
+func (h handler) JoinWaitlist(c *gin.Context) {
+	mid, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.JoinWaitlist.01", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "INVALID_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	var req JoinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).Error("Handler.JoinWaitlist.02", "error", err)
+
+		c.JSON(http.StatusBadRequest, Response{
+			Code:      http.StatusBadRequest,
+			Message:   "BAD_REQUEST",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.JoinWaitlist.03")
+	if !ok {
+		return
+	}
+	entry, err := h.waitlistUseCase.Join(user, mid, req.PartySize)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.JoinWaitlist.04", "error", err)
+
+		status := http.StatusNotFound
+		switch {
+		case errors.Is(err, ErrNotPermitted):
+			status = http.StatusForbidden
+		case errors.Is(err, seating.ErrPartyTooLarge):
+			status = http.StatusBadRequest
+		case errors.Is(err, ErrAlreadyWaiting), errors.Is(err, ErrSeatsAvailable), errors.Is(err, ErrShowStarted):
+			status = http.StatusConflict
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_JOIN",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Code:    http.StatusCreated,
+		Message: "JOINED_WAITLIST",
+		Data:    entry,
+	})
+}
+
+func (h handler) GetWaitlist(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.GetWaitlist.01")
+	if !ok {
+		return
+	}
+	entries, err := h.waitlistUseCase.GetEntries(user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetWaitlist.02", "error", err)
+
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:      http.StatusInternalServerError,
+			Message:   "FAILED_USECASE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    entries,
+	})
+}
+
+func (h handler) LeaveWaitlist(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.LeaveWaitlist.01")
+	if !ok {
+		return
+	}
+	entry, err := h.waitlistUseCase.Leave(user, c.Param("id"))
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.LeaveWaitlist.02", "error", err)
+
+		status := http.StatusNotFound
+		if errors.Is(err, ErrEntryClosed) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, Response{
+			Code:      status,
+			Message:   "FAILED_LEAVE",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "LEFT_WAITLIST",
+		Data:    entry,
+	})
+}
+
+func (h handler) currentUser(c *gin.Context, tag string) (entities.User, bool) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.booking.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error(tag, "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return entities.User{}, false
+	}
+	return user, true
+}
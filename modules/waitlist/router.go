@@ -0,0 +1,12 @@
+package waitlist
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+	UserRouter := r.Group("/user", middleware)
+	UserRouter.POST("/waitlist/:movie_id", h.JoinWaitlist)
+	UserRouter.GET("/waitlist", h.GetWaitlist)
+	UserRouter.DELETE("/waitlist/:id", h.LeaveWaitlist)
+}
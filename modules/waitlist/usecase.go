@@ -0,0 +1,338 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/notify"
+	"movie-app-go/modules/seating"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAlreadyWaiting = errors.New("ALREADY_ON_WAITLIST")
+	ErrSeatsAvailable = errors.New("SEATS_AVAILABLE")
+	ErrShowStarted    = errors.New("SHOW_ALREADY_STARTED")
+	ErrNotPermitted   = errors.New("NOT_PERMITTED")
+	ErrEntryNotFound  = errors.New("WAITLIST_ENTRY_NOT_FOUND")
+	ErrEntryClosed    = errors.New("WAITLIST_ENTRY_CLOSED")
+)
+
+// Booking is the part of the user use case the waitlist offers seats through
+type Booking interface {
+	GetUser(username string) (entities.User, error)
+	GetMovie(id int) (entities.Movie, error)
+	NotPermitted(m entities.Movie, u entities.User) bool
+	SuggestSeats(m entities.Movie, u entities.User, opts seating.Options) ([]entities.Seat, error)
+	HoldSeatsUntil(seat []entities.Seat, m *entities.Movie, u entities.User, until time.Time) ([]entities.Seat, error)
+	ReleaseSeats(seat []entities.Seat, m *entities.Movie, u entities.User) ([]entities.Seat, error)
+}
+
+type useCase struct {
+	// mu serialises joining, leaving and offering so an entry is never
+	// offered seats twice
+	mu            sync.Mutex
+	waitlistRepo  repositories.WaitlistRepositoryInterface
+	booking       Booking
+	notifier      notify.Notifier
+	offerDuration time.Duration
+}
+
+type UseCaseInterface interface {
+	Join(u entities.User, movieID, partySize int) (entities.WaitlistEntry, error)
+	Leave(u entities.User, id string) (entities.WaitlistEntry, error)
+	GetEntries(u entities.User) ([]entities.WaitlistEntry, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+func NewUseCase(waitlistRepo repositories.WaitlistRepositoryInterface, booking Booking, notifier notify.Notifier, offerDuration time.Duration) UseCaseInterface {
+	return &useCase{
+		waitlistRepo:  waitlistRepo,
+		booking:       booking,
+		notifier:      notifier,
+		offerDuration: offerDuration,
+	}
+}
+
+// Join queues the user for a movie. Joining is refused while the party can
+// still book right away
+func (usecase *useCase) Join(u entities.User, movieID, partySize int) (entities.WaitlistEntry, error) {
+	movie, err := usecase.booking.GetMovie(movieID)
+	if err != nil {
+		return entities.WaitlistEntry{}, err
+	}
+	now := time.Now()
+	if !now.Before(movie.Showtime) {
+		return entities.WaitlistEntry{}, ErrShowStarted
+	}
+	if usecase.booking.NotPermitted(movie, u) {
+		return entities.WaitlistEntry{}, ErrNotPermitted
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	entries, err := usecase.waitlistRepo.ReadByUser(u.ID)
+	if err != nil {
+		return entities.WaitlistEntry{}, err
+	}
+	for _, entry := range entries {
+		if entry.MovieID == movieID && entry.Active() {
+			return entities.WaitlistEntry{}, ErrAlreadyWaiting
+		}
+	}
+	if _, err := usecase.booking.SuggestSeats(movie, u, seating.Options{PartySize: partySize}); err == nil {
+		return entities.WaitlistEntry{}, ErrSeatsAvailable
+	} else if !errors.Is(err, seating.ErrNoSeats) {
+		return entities.WaitlistEntry{}, err
+	}
+
+	UUID, err := uuid.NewRandom()
+	if err != nil {
+		return entities.WaitlistEntry{}, err
+	}
+	entry := entities.WaitlistEntry{
+		ID:         UUID.String(),
+		MovieID:    movieID,
+		UserID:     u.ID,
+		Username:   u.Username,
+		PartySize:  partySize,
+		Status:     entities.WaitlistWaiting,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if err := usecase.waitlistRepo.Create(entry); err != nil {
+		return entities.WaitlistEntry{}, err
+	}
+	return entry, nil
+}
+
+// Leave drops the user from the queue, giving up any seats offered to them
+func (usecase *useCase) Leave(u entities.User, id string) (entities.WaitlistEntry, error) {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	entry, err := usecase.waitlistRepo.Read(id)
+	if err != nil || entry.UserID != u.ID {
+		return entities.WaitlistEntry{}, ErrEntryNotFound
+	}
+	if !entry.Active() {
+		return entities.WaitlistEntry{}, ErrEntryClosed
+	}
+	if entry.Status == entities.WaitlistOffered {
+		usecase.releaseOffer(entry, u)
+	}
+	entry.Status = entities.WaitlistLeft
+	entry.Updated_at = time.Now()
+	if err := usecase.waitlistRepo.Update(entry); err != nil {
+		return entities.WaitlistEntry{}, err
+	}
+	return entry, nil
+}
+
+func (usecase *useCase) GetEntries(u entities.User) ([]entities.WaitlistEntry, error) {
+	return usecase.waitlistRepo.ReadByUser(u.ID)
+}
+
+// Run offers freed seats and expires unused offers every interval until
+// the context is cancelled
+func (usecase *useCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := usecase.sweep(now); err != nil {
+				slog.Error("UseCase.Waitlist.Run.01", "error", err)
+			}
+		}
+	}
+}
+
+// sweep walks each movie's queue in joining order. Offers are settled
+// first so seats of lapsed offers pass to the next party in the same pass.
+// A party too large for the seats left does not block smaller parties
+// behind it
+func (usecase *useCase) sweep(now time.Time) error {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	entries, err := usecase.waitlistRepo.ReadAll()
+	if err != nil {
+		return err
+	}
+	queues := make(map[int][]entities.WaitlistEntry)
+	var order []int
+	for _, entry := range entries {
+		if !entry.Active() {
+			continue
+		}
+		if _, ok := queues[entry.MovieID]; !ok {
+			order = append(order, entry.MovieID)
+		}
+		queues[entry.MovieID] = append(queues[entry.MovieID], entry)
+	}
+
+	for _, movieID := range order {
+		queue := queues[movieID]
+		for i := range queue {
+			if queue[i].Status == entities.WaitlistOffered {
+				queue[i] = usecase.settleOffer(queue[i], now)
+			}
+		}
+		for _, entry := range queue {
+			if entry.Status == entities.WaitlistWaiting {
+				usecase.offer(entry, now)
+			}
+		}
+	}
+	return nil
+}
+
+// settleOffer closes an offer the user booked from or let lapse
+func (usecase *useCase) settleOffer(entry entities.WaitlistEntry, now time.Time) entities.WaitlistEntry {
+	user, err := usecase.booking.GetUser(entry.Username)
+	if err != nil {
+		return usecase.close(entry, entities.WaitlistLeft, now)
+	}
+	if booked(user, entry) {
+		return usecase.close(entry, entities.WaitlistBooked, now)
+	}
+	if now.Before(entry.Offer_expires_at) {
+		return entry
+	}
+
+	usecase.releaseOffer(entry, user)
+	entry = usecase.close(entry, entities.WaitlistExpired, now)
+	usecase.send(entry, notify.Notification{
+		Kind:    notify.WaitlistExpired,
+		Subject: "Waitlist offer expired",
+		Message: fmt.Sprintf("The seats held for you for movie %d were passed to the next person in line", entry.MovieID),
+	})
+	return entry
+}
+
+// offer holds seats for a waiting party if the movie has room for it
+func (usecase *useCase) offer(entry entities.WaitlistEntry, now time.Time) {
+	movie, err := usecase.booking.GetMovie(entry.MovieID)
+	if err != nil {
+		return
+	}
+	if !now.Before(movie.Showtime) {
+		usecase.close(entry, entities.WaitlistExpired, now)
+		return
+	}
+	user, err := usecase.booking.GetUser(entry.Username)
+	if err != nil {
+		usecase.close(entry, entities.WaitlistLeft, now)
+		return
+	}
+	seats, err := usecase.booking.SuggestSeats(movie, user, seating.Options{PartySize: entry.PartySize})
+	if err != nil {
+		return
+	}
+	until := now.Add(usecase.offerDuration)
+	if movie.Showtime.Before(until) {
+		until = movie.Showtime
+	}
+	held, err := usecase.booking.HoldSeatsUntil(seats, &movie, user, until)
+	if err != nil {
+		slog.Error("UseCase.Waitlist.Offer.01", "error", err, "entry_id", entry.ID)
+		return
+	}
+
+	entry.Status = entities.WaitlistOffered
+	entry.Seats = labels(held)
+	entry.Offer_expires_at = until
+	entry.Updated_at = now
+	if err := usecase.waitlistRepo.Update(entry); err != nil {
+		usecase.booking.ReleaseSeats(held, &movie, user)
+		slog.Error("UseCase.Waitlist.Offer.02", "error", err, "entry_id", entry.ID)
+		return
+	}
+	usecase.send(entry, notify.Notification{
+		Kind:    notify.WaitlistOffer,
+		Subject: fmt.Sprintf("Seats available for %s", movie.Title),
+		Message: fmt.Sprintf("Seats %v are held for you until %s, book them before then", entry.Seats, until.Format("15:04")),
+		Data: map[string]any{
+			"movie_id":   movie.ID,
+			"seats":      entry.Seats,
+			"expires_at": until,
+		},
+	})
+}
+
+func (usecase *useCase) releaseOffer(entry entities.WaitlistEntry, u entities.User) {
+	movie, err := usecase.booking.GetMovie(entry.MovieID)
+	if err != nil {
+		return
+	}
+	// Only the seat positions are read here, their state belongs to the
+	// booking side and its lock
+	var held []entities.Seat
+	for i := range movie.Seats {
+		seat := entities.Seat{Row: movie.Seats[i].Row, Number: movie.Seats[i].Number}
+		for _, label := range entry.Seats {
+			if label == fmt.Sprintf("%s%d", seat.Row, seat.Number) {
+				held = append(held, seat)
+			}
+		}
+	}
+	// Seats already booked or whose hold lapsed are skipped
+	usecase.booking.ReleaseSeats(held, &movie, u)
+}
+
+func (usecase *useCase) close(entry entities.WaitlistEntry, status string, now time.Time) entities.WaitlistEntry {
+	entry.Status = status
+	entry.Updated_at = now
+	if err := usecase.waitlistRepo.Update(entry); err != nil {
+		slog.Error("UseCase.Waitlist.Close.01", "error", err, "entry_id", entry.ID)
+	}
+	return entry
+}
+
+func (usecase *useCase) send(entry entities.WaitlistEntry, n notify.Notification) {
+	n.UserID = entry.UserID
+	n.Username = entry.Username
+	if err := usecase.notifier.Notify(n); err != nil {
+		slog.Error("UseCase.Waitlist.Notify.01", "error", err, "entry_id", entry.ID, "kind", n.Kind)
+	}
+}
+
+// booked tells whether the user bought any of the offered seats after the
+// offer was made
+func booked(u entities.User, entry entities.WaitlistEntry) bool {
+	offered := make(map[string]bool, len(entry.Seats))
+	for _, label := range entry.Seats {
+		offered[label] = true
+	}
+	for _, ticket := range u.Ticket {
+		if ticket.Movie.ID != entry.MovieID || ticket.Created_At.Before(entry.Updated_at) {
+			continue
+		}
+		for _, seat := range ticket.Seats {
+			if offered[fmt.Sprintf("%s%d", seat.Row, seat.Number)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func labels(seats []entities.Seat) []string {
+	out := make([]string, 0, len(seats))
+	for _, seat := range seats {
+		out = append(out, fmt.Sprintf("%s%d", seat.Row, seat.Number))
+	}
+	return out
+}
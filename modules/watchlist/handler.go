@@ -0,0 +1,134 @@
+package watchlist
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/auth"
+	"movie-app-go/modules/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Accounts resolves the caller of a watchlist route
+type Accounts interface {
+	GetUser(username string) (entities.User, error)
+}
+
+type handler struct {
+	watchlistUseCase UseCaseInterface
+	accounts         Accounts
+}
+
+type HandlerInterface interface {
+	AddToWatchlist(c *gin.Context)
+	GetWatchlist(c *gin.Context)
+	RemoveFromWatchlist(c *gin.Context)
+}
+
+func NewHandler(watchlistUseCase UseCaseInterface, accounts Accounts) HandlerInterface {
+	return &handler{
+		watchlistUseCase: watchlistUseCase,
+		accounts:         accounts,
+	}
+}
+
+func (h handler) AddToWatchlist(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.AddToWatchlist.01", "error", err)
+		h.failed(c, "BAD_REQUEST", err)
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.AddToWatchlist.02")
+	if !ok {
+		return
+	}
+	item, err := h.watchlistUseCase.Add(user, movieID)
+	if err != nil {
+		logger.FromContext(c).Warn("Handler.AddToWatchlist.03", "error", err)
+		h.failed(c, "FAILED_ADD_WATCHLIST", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "ADDED_TO_WATCHLIST",
+		Data:    item,
+	})
+}
+
+func (h handler) GetWatchlist(c *gin.Context) {
+	user, ok := h.currentUser(c, "Handler.GetWatchlist.01")
+	if !ok {
+		return
+	}
+	entries, err := h.watchlistUseCase.GetEntries(user)
+	if err != nil {
+		logger.FromContext(c).Error("Handler.GetWatchlist.02", "error", err)
+		h.failed(c, "FAILED_USECASE", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "SUCCESS",
+		Data:    entries,
+	})
+}
+
+func (h handler) RemoveFromWatchlist(c *gin.Context) {
+	movieID, err := strconv.Atoi(c.Param("movie_id"))
+	if err != nil {
+		logger.FromContext(c).Error("Handler.RemoveFromWatchlist.01", "error", err)
+		h.failed(c, "BAD_REQUEST", err)
+		return
+	}
+	user, ok := h.currentUser(c, "Handler.RemoveFromWatchlist.02")
+	if !ok {
+		return
+	}
+	if err := h.watchlistUseCase.Remove(user, movieID); err != nil {
+		logger.FromContext(c).Warn("Handler.RemoveFromWatchlist.03", "error", err)
+		h.failed(c, "FAILED_REMOVE_WATCHLIST", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    http.StatusOK,
+		Message: "REMOVED_FROM_WATCHLIST",
+		Data:    movieID,
+	})
+}
+
+func (h handler) currentUser(c *gin.Context, tag string) (entities.User, bool) {
+	authInfo, _ := c.Get("AuthInfo")
+	user, err := h.accounts.GetUser(authInfo.(auth.AuthInfo).Username)
+	if err != nil {
+		logger.FromContext(c).Error(tag, "error", err)
+
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:      http.StatusUnauthorized,
+			Message:   "UNAUTHORIZED",
+			Data:      err.Error(),
+			RequestID: logger.RequestID(c),
+		})
+		return entities.User{}, false
+	}
+	return user, true
+}
+
+func (h handler) failed(c *gin.Context, message string, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrMovieNotFound) || errors.Is(err, ErrNotListed) {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, Response{
+		Code:      status,
+		Message:   message,
+		Data:      err.Error(),
+		RequestID: logger.RequestID(c),
+	})
+}
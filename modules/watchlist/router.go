@@ -0,0 +1,12 @@
+package watchlist
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRouter(r *gin.Engine, h HandlerInterface, middleware gin.HandlerFunc) {
+	UserRouter := r.Group("/user", middleware)
+	UserRouter.POST("/watchlist/:movie_id", h.AddToWatchlist)
+	UserRouter.GET("/watchlist", h.GetWatchlist)
+	UserRouter.DELETE("/watchlist/:movie_id", h.RemoveFromWatchlist)
+}
@@ -0,0 +1,214 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"movie-app-go/entities"
+	"movie-app-go/modules/notify"
+	"movie-app-go/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMovieNotFound = errors.New("MOVIE_NOT_FOUND")
+	ErrNotListed     = errors.New("NOT_ON_WATCHLIST")
+)
+
+// Entry is a watchlist item with the movie details a listing needs
+type Entry struct {
+	entities.WatchlistItem
+	Title        string        `json:"title"`
+	Poster_url   string        `json:"poster_url"`
+	Release_date entities.Date `json:"release_date"`
+	Released     bool          `json:"released"`
+	Showtime     time.Time     `json:"showtime"`
+}
+
+type useCase struct {
+	// mu keeps one item per user and movie and stops the reminder job
+	// from racing with removals
+	mu            sync.Mutex
+	watchlistRepo repositories.WatchlistRepositoryInterface
+	movieRepo     repositories.MovieRepositoryInterface
+	notifier      notify.Notifier
+}
+
+type UseCaseInterface interface {
+	Add(u entities.User, movieID int) (entities.WatchlistItem, error)
+	Remove(u entities.User, movieID int) error
+	GetEntries(u entities.User) ([]Entry, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+func NewUseCase(watchlistRepo repositories.WatchlistRepositoryInterface, movieRepo repositories.MovieRepositoryInterface, notifier notify.Notifier) UseCaseInterface {
+	return &useCase{
+		watchlistRepo: watchlistRepo,
+		movieRepo:     movieRepo,
+		notifier:      notifier,
+	}
+}
+
+// Add saves a movie to the user's watchlist, adding it again returns the
+// saved item. Reminders only cover what changes after this point
+func (usecase *useCase) Add(u entities.User, movieID int) (entities.WatchlistItem, error) {
+	movie, err := usecase.movieRepo.Read(movieID)
+	if err != nil {
+		return entities.WatchlistItem{}, ErrMovieNotFound
+	}
+
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	if item, ok := usecase.find(u.ID, movieID); ok {
+		return item, nil
+	}
+	now := time.Now()
+	item := entities.WatchlistItem{
+		ID:               uuid.NewString(),
+		UserID:           u.ID,
+		Username:         u.Username,
+		MovieID:          movieID,
+		Seen_showtime:    movie.Showtime,
+		Release_notified: movie.Released(now),
+		Added_at:         now,
+	}
+	if err := usecase.watchlistRepo.Create(item); err != nil {
+		return entities.WatchlistItem{}, err
+	}
+	return item, nil
+}
+
+func (usecase *useCase) Remove(u entities.User, movieID int) error {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	item, ok := usecase.find(u.ID, movieID)
+	if !ok {
+		return ErrNotListed
+	}
+	return usecase.watchlistRepo.Delete(item.ID)
+}
+
+// GetEntries lists the user's watchlist, most recently added first
+func (usecase *useCase) GetEntries(u entities.User) ([]Entry, error) {
+	items, err := usecase.watchlistRepo.ReadByUser(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entries := make([]Entry, 0, len(items))
+	for _, item := range items {
+		entry := Entry{WatchlistItem: item}
+		if movie, err := usecase.movieRepo.Read(item.MovieID); err == nil {
+			entry.Title = movie.Title
+			entry.Poster_url = movie.Poster_url
+			entry.Release_date = movie.Release_date
+			entry.Released = movie.Released(now)
+			entry.Showtime = movie.Showtime
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].Added_at.After(entries[b].Added_at)
+	})
+	return entries, nil
+}
+
+// Run sends reminders every interval until ctx is cancelled
+func (usecase *useCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := usecase.remind(now); err != nil {
+				slog.Error("UseCase.Watchlist.Run.01", "error", err)
+			}
+		}
+	}
+}
+
+// remind tells users when a watchlisted movie is released and when it gets
+// a showtime they have not been told about
+func (usecase *useCase) remind(now time.Time) error {
+	usecase.mu.Lock()
+	defer usecase.mu.Unlock()
+
+	items, err := usecase.watchlistRepo.ReadAll()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		movie, err := usecase.movieRepo.Read(item.MovieID)
+		if err != nil {
+			continue
+		}
+		changed := false
+		if !item.Release_notified && movie.Released(now) {
+			usecase.send(item, notify.Notification{
+				Kind:    notify.WatchlistReleased,
+				Subject: fmt.Sprintf("%s is out now", movie.Title),
+				Message: fmt.Sprintf("%s from your watchlist was released on %s", movie.Title, movie.Release_date),
+				Data:    map[string]any{"movie_id": movie.ID},
+			})
+			item.Release_notified = true
+			changed = true
+		}
+		if !movie.Showtime.IsZero() && !movie.Showtime.Equal(item.Seen_showtime) {
+			if movie.Showtime.After(now) && !dailyRepeat(item.Seen_showtime, movie.Showtime) {
+				usecase.send(item, notify.Notification{
+					Kind:    notify.WatchlistShowtime,
+					Subject: fmt.Sprintf("New showtime for %s", movie.Title),
+					Message: fmt.Sprintf("%s from your watchlist is showing at %s", movie.Title, movie.Showtime.Format("2006-01-02 15:04")),
+					Data:    map[string]any{"movie_id": movie.ID, "showtime": movie.Showtime},
+				})
+			}
+			item.Seen_showtime = movie.Showtime
+			changed = true
+		}
+		if changed {
+			if err := usecase.watchlistRepo.Update(item); err != nil {
+				slog.Error("UseCase.Watchlist.Remind.01", "error", err, "item_id", item.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// dailyRepeat tells whether a showtime is only a later day's screening at
+// the time already seen, which is not news
+func dailyRepeat(seen, showtime time.Time) bool {
+	if seen.IsZero() || !showtime.After(seen) {
+		return false
+	}
+	seen, showtime = seen.In(time.Local), showtime.In(time.Local)
+	return seen.Hour() == showtime.Hour() && seen.Minute() == showtime.Minute()
+}
+
+func (usecase *useCase) find(userID string, movieID int) (entities.WatchlistItem, bool) {
+	items, _ := usecase.watchlistRepo.ReadByUser(userID)
+	for _, item := range items {
+		if item.MovieID == movieID {
+			return item, true
+		}
+	}
+	return entities.WatchlistItem{}, false
+}
+
+func (usecase *useCase) send(item entities.WatchlistItem, n notify.Notification) {
+	n.UserID = item.UserID
+	n.Username = item.Username
+	if err := usecase.notifier.Notify(n); err != nil {
+		slog.Error("UseCase.Watchlist.Notify.01", "error", err, "kind", n.Kind)
+	}
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"movie-app-go/entities"
+)
+
+// ParseMovies decodes a movie catalog. A movie that does not decode, such as
+// one with a malformed release date, is left out and its error is returned
+// in skipped so one bad entry does not take the whole catalog down
+func ParseMovies(data []byte) (movies []entities.Movie, skipped []error, err error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+	movies = make([]entities.Movie, 0, len(raw))
+	for i, entry := range raw {
+		var movie entities.Movie
+		if err := json.Unmarshal(entry, &movie); err != nil {
+			// Name the movie as best we can when the full entry is unreadable
+			var header struct {
+				ID    int    `json:"id"`
+				Title string `json:"title"`
+			}
+			json.Unmarshal(entry, &header)
+			skipped = append(skipped, fmt.Errorf("movie %d at position %d (%q): %w", header.ID, i, header.Title, err))
+			continue
+		}
+		movies = append(movies, movie)
+	}
+	return movies, skipped, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"maps"
+	"movie-app-go/entities"
+	"slices"
+)
+
+// The repositories hand out and keep their own copies of what they store,
+// so a caller changing a record, or a slice inside one, never changes what
+// another goroutine reads until it is written back
+
+func cloneAll[T any](data []T, clone func(T) T) []T {
+	out := make([]T, len(data))
+	for i, item := range data {
+		out[i] = clone(item)
+	}
+	return out
+}
+
+func cloneMovie(m entities.Movie) entities.Movie {
+	m.Genres = slices.Clone(m.Genres)
+	m.Cast = slices.Clone(m.Cast)
+	m.Seat_pricing = maps.Clone(m.Seat_pricing)
+	m.Seats = slices.Clone(m.Seats)
+	if m.Rating != nil {
+		rating := *m.Rating
+		m.Rating = &rating
+	}
+	return m
+}
+
+func cloneBreakdown(b entities.PriceBreakdown) entities.PriceBreakdown {
+	b.Items = slices.Clone(b.Items)
+	b.Adjustments = slices.Clone(b.Adjustments)
+	return b
+}
+
+func cloneTicket(t entities.Ticket) entities.Ticket {
+	t.Movie = cloneMovie(t.Movie)
+	t.Seats = slices.Clone(t.Seats)
+	t.Breakdown = cloneBreakdown(t.Breakdown)
+	t.Attendees = slices.Clone(t.Attendees)
+	t.PreviousOwners = slices.Clone(t.PreviousOwners)
+	return t
+}
+
+func cloneUser(u entities.User) entities.User {
+	u.Ticket = cloneAll(u.Ticket, cloneTicket)
+	return u
+}
+
+func cloneQuote(q entities.PriceQuote) entities.PriceQuote {
+	q.Seats = slices.Clone(q.Seats)
+	q.Breakdown = cloneBreakdown(q.Breakdown)
+	return q
+}
+
+func clonePromo(p entities.PromoCode) entities.PromoCode {
+	p.Movie_ids = slices.Clone(p.Movie_ids)
+	return p
+}
+
+func cloneGroup(g entities.GroupBooking) entities.GroupBooking {
+	g.Seats = slices.Clone(g.Seats)
+	g.Breakdown = cloneBreakdown(g.Breakdown)
+	g.Shares = slices.Clone(g.Shares)
+	for i := range g.Shares {
+		g.Shares[i].Seats = slices.Clone(g.Shares[i].Seats)
+	}
+	return g
+}
+
+func cloneOffer(o entities.TicketOffer) entities.TicketOffer {
+	o.Seats = slices.Clone(o.Seats)
+	return o
+}
+
+func cloneWaitlistEntry(e entities.WaitlistEntry) entities.WaitlistEntry {
+	e.Seats = slices.Clone(e.Seats)
+	return e
+}
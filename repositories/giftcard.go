@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"slices"
+	"sync"
+)
+
+type GiftCardRepository struct {
+	mu   sync.RWMutex
+	data []entities.GiftCard
+}
+type GiftCardRepositoryInterface interface {
+	Create(card entities.GiftCard) error
+	ReadByHash(hash string) (entities.GiftCard, error)
+	ReadAll() ([]entities.GiftCard, error)
+	Update(card entities.GiftCard) error
+}
+
+func NewGiftCardRepository(data []entities.GiftCard) GiftCardRepositoryInterface {
+	return &GiftCardRepository{
+		data: slices.Clone(data),
+	}
+}
+
+func (repo *GiftCardRepository) Create(card entities.GiftCard) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingCard := range repo.data {
+		if existingCard.ID == card.ID || existingCard.Code_hash == card.Code_hash {
+			return errors.New("gift card with the same code already exists")
+		}
+	}
+	repo.data = append(repo.data, card)
+	return nil
+}
+
+func (repo *GiftCardRepository) ReadByHash(hash string) (entities.GiftCard, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, card := range repo.data {
+		if card.Code_hash == hash {
+			return card, nil
+		}
+	}
+	return entities.GiftCard{}, errors.New("NOT_FOUND")
+}
+
+func (repo *GiftCardRepository) ReadAll() ([]entities.GiftCard, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return slices.Clone(repo.data), nil
+}
+
+func (repo *GiftCardRepository) Update(card entities.GiftCard) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingCard := range repo.data {
+		if existingCard.ID == card.ID {
+			repo.data[i] = card
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"sync"
+)
+
+type GroupBookingRepository struct {
+	mu   sync.RWMutex
+	data []entities.GroupBooking
+}
+type GroupBookingRepositoryInterface interface {
+	Create(group entities.GroupBooking) error
+	Read(id string) (entities.GroupBooking, error)
+	ReadAll() ([]entities.GroupBooking, error)
+	Update(group entities.GroupBooking) error
+}
+
+func NewGroupBookingRepository(data []entities.GroupBooking) GroupBookingRepositoryInterface {
+	return &GroupBookingRepository{
+		data: cloneAll(data, cloneGroup),
+	}
+}
+
+func (repo *GroupBookingRepository) Create(group entities.GroupBooking) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingGroup := range repo.data {
+		if existingGroup.ID == group.ID {
+			return errors.New("group booking with the same id already exists")
+		}
+	}
+	repo.data = append(repo.data, cloneGroup(group))
+	return nil
+}
+
+func (repo *GroupBookingRepository) Read(id string) (entities.GroupBooking, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, group := range repo.data {
+		if group.ID == id {
+			return cloneGroup(group), nil
+		}
+	}
+	return entities.GroupBooking{}, errors.New("NOT_FOUND")
+}
+
+func (repo *GroupBookingRepository) ReadAll() ([]entities.GroupBooking, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return cloneAll(repo.data, cloneGroup), nil
+}
+
+func (repo *GroupBookingRepository) Update(group entities.GroupBooking) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingGroup := range repo.data {
+		if existingGroup.ID == group.ID {
+			repo.data[i] = cloneGroup(group)
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"movie-app-go/entities"
+	"slices"
+	"sync"
+)
+
+type LoyaltyRepository struct {
+	mu   sync.RWMutex
+	data []entities.LoyaltyEntry
+}
+type LoyaltyRepositoryInterface interface {
+	Create(entry entities.LoyaltyEntry) error
+	ReadByUser(userID string) ([]entities.LoyaltyEntry, error)
+	ReadByTicket(ticketID string) ([]entities.LoyaltyEntry, error)
+}
+
+func NewLoyaltyRepository(data []entities.LoyaltyEntry) LoyaltyRepositoryInterface {
+	return &LoyaltyRepository{
+		data: slices.Clone(data),
+	}
+}
+
+func (repo *LoyaltyRepository) Create(entry entities.LoyaltyEntry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.data = append(repo.data, entry)
+	return nil
+}
+
+func (repo *LoyaltyRepository) ReadByUser(userID string) ([]entities.LoyaltyEntry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var entries []entities.LoyaltyEntry
+	for _, entry := range repo.data {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (repo *LoyaltyRepository) ReadByTicket(ticketID string) ([]entities.LoyaltyEntry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var entries []entities.LoyaltyEntry
+	for _, entry := range repo.data {
+		if entry.TicketID == ticketID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
@@ -3,31 +3,96 @@ package repositories
 import (
 	"errors"
 	"movie-app-go/entities"
+	"slices"
+	"sync"
+	"time"
 )
 
 type MovieRepository struct {
+	mu   sync.RWMutex
 	data []entities.Movie
+	// version counts the writes, modified is when the last one happened
+	version  uint64
+	modified time.Time
 }
 type MovieRepositoryInterface interface {
 	Read(id int) (entities.Movie, error)
 	ReadAll() ([]entities.Movie, error)
+	Update(movie entities.Movie) error
+	UpdateScreening(id int, showtime time.Time, seats []entities.Seat) error
+	Version() (uint64, time.Time)
 }
 
 func NewMovieRepository(data []entities.Movie) MovieRepositoryInterface {
 	return &MovieRepository{
-		data: data,
+		data: cloneAll(data, cloneMovie),
 	}
 }
 
-func (repo MovieRepository) Read(id int) (entities.Movie, error) {
+func (repo *MovieRepository) Read(id int) (entities.Movie, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
 	for _, movie := range repo.data {
 		if movie.ID == id {
-			return movie, nil
+			return cloneMovie(movie), nil
 		}
 	}
 	return entities.Movie{}, errors.New("EMPTY_DATA")
 
 }
-func (repo MovieRepository) ReadAll() ([]entities.Movie, error) {
-	return repo.data, nil
+func (repo *MovieRepository) ReadAll() ([]entities.Movie, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return cloneAll(repo.data, cloneMovie), nil
+}
+
+// Update stores the movie's details. Its showtime and seats are kept as
+// stored, they only change through UpdateScreening
+func (repo *MovieRepository) Update(movie entities.Movie) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existing := range repo.data {
+		if existing.ID == movie.ID {
+			movie = cloneMovie(movie)
+			movie.Showtime = existing.Showtime
+			movie.Seats = existing.Seats
+			repo.data[i] = movie
+			repo.changed()
+			return nil
+		}
+	}
+	return errors.New("EMPTY_DATA")
+}
+
+// UpdateScreening stores the movie's showtime and the state of its seats
+func (repo *MovieRepository) UpdateScreening(id int, showtime time.Time, seats []entities.Seat) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existing := range repo.data {
+		if existing.ID == id {
+			repo.data[i].Showtime = showtime
+			repo.data[i].Seats = slices.Clone(seats)
+			repo.changed()
+			return nil
+		}
+	}
+	return errors.New("EMPTY_DATA")
+}
+
+// Version tells how many writes the movies have seen and when the last one
+// happened, the zero time before any
+func (repo *MovieRepository) Version() (uint64, time.Time) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return repo.version, repo.modified
+}
+
+func (repo *MovieRepository) changed() {
+	repo.version++
+	repo.modified = time.Now()
 }
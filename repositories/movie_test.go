@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+)
+
+func TestMovieRepositoryHandsOutCopies(t *testing.T) {
+	showtime := time.Date(2026, 1, 2, 19, 0, 0, 0, time.UTC)
+	loaded := []entities.Movie{{ID: 1, Title: "Alpha", Genres: []string{"Drama"}, Showtime: showtime, Seats: GenerateSeats()}}
+	repo := NewMovieRepository(loaded)
+	loaded[0].Seats[0].Booked = true
+
+	tests := []struct {
+		name   string
+		change func(m *entities.Movie)
+	}{
+		{name: "read seats", change: func(m *entities.Movie) { m.Seats[0].Booked = true }},
+		{name: "read genres", change: func(m *entities.Movie) { m.Genres[0] = "Horror" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movie, _ := repo.Read(1)
+			tt.change(&movie)
+			all, _ := repo.ReadAll()
+			tt.change(&all[0])
+
+			stored, _ := repo.Read(1)
+			if stored.Seats[0].Booked || stored.Genres[0] != "Drama" {
+				t.Errorf("stored movie changed through a copy: %+v", stored)
+			}
+		})
+	}
+}
+
+func TestMovieRepositoryUpdateKeepsScreening(t *testing.T) {
+	showtime := time.Date(2026, 1, 2, 19, 0, 0, 0, time.UTC)
+	repo := NewMovieRepository([]entities.Movie{{ID: 1, Title: "Alpha", Showtime: showtime, Seats: GenerateSeats()}})
+
+	// A seat is booked while an admin edits the details of a stale copy
+	stale, _ := repo.Read(1)
+	seats := stale.Seats
+	seats[0].Booked = true
+	if err := repo.UpdateScreening(1, showtime, seats); err != nil {
+		t.Fatal(err)
+	}
+	stale.Title = "Alpha (Director's Cut)"
+	if err := repo.Update(stale); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := repo.Read(1)
+	if stored.Title != "Alpha (Director's Cut)" || !stored.Seats[0].Booked || !stored.Showtime.Equal(showtime) {
+		t.Errorf("stored = %q, A1 booked %v, showtime %v", stored.Title, stored.Seats[0].Booked, stored.Showtime)
+	}
+}
+
+func TestMovieRepositoryVersionCountsWrites(t *testing.T) {
+	showtime := time.Date(2026, 1, 2, 19, 0, 0, 0, time.UTC)
+	repo := NewMovieRepository([]entities.Movie{{ID: 1, Title: "Alpha", Showtime: showtime, Seats: GenerateSeats()}})
+	if version, modified := repo.Version(); version != 0 || !modified.IsZero() {
+		t.Fatalf("fresh repository at version %d modified %v", version, modified)
+	}
+
+	movie, _ := repo.Read(1)
+	repo.Update(movie)
+	repo.UpdateScreening(1, showtime, movie.Seats)
+	repo.UpdateScreening(2, showtime, movie.Seats)
+
+	if version, modified := repo.Version(); version != 2 || modified.IsZero() {
+		t.Errorf("version = %d modified %v after two writes", version, modified)
+	}
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"sync"
+	"time"
+)
+
+type PromoRepository struct {
+	mu          sync.RWMutex
+	data        []entities.PromoCode
+	redemptions []entities.PromoRedemption
+}
+type PromoRepositoryInterface interface {
+	Create(promo entities.PromoCode) error
+	Read(code string) (entities.PromoCode, error)
+	ReadAll() ([]entities.PromoCode, error)
+	Update(promo entities.PromoCode) error
+
+	CreateRedemption(redemption entities.PromoRedemption) error
+	ReadRedemptions(code string) ([]entities.PromoRedemption, error)
+	DeleteRedemptionByTicket(ticketID string) (entities.PromoRedemption, error)
+}
+
+func NewPromoRepository(data []entities.PromoCode) PromoRepositoryInterface {
+	return &PromoRepository{
+		data: cloneAll(data, clonePromo),
+	}
+}
+
+func (repo *PromoRepository) Create(promo entities.PromoCode) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingPromo := range repo.data {
+		if existingPromo.Code == promo.Code {
+			return errors.New("promo with the same code already exists")
+		}
+	}
+	repo.data = append(repo.data, clonePromo(promo))
+	return nil
+}
+
+func (repo *PromoRepository) Read(code string) (entities.PromoCode, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, promo := range repo.data {
+		if promo.Code == code {
+			return clonePromo(promo), nil
+		}
+	}
+	return entities.PromoCode{}, errors.New("NOT_FOUND")
+}
+
+func (repo *PromoRepository) ReadAll() ([]entities.PromoCode, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return cloneAll(repo.data, clonePromo), nil
+}
+
+func (repo *PromoRepository) Update(promo entities.PromoCode) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingPromo := range repo.data {
+		if existingPromo.Code == promo.Code {
+			promo.Updated_at = time.Now()
+			repo.data[i] = clonePromo(promo)
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
+
+func (repo *PromoRepository) CreateRedemption(redemption entities.PromoRedemption) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.redemptions = append(repo.redemptions, redemption)
+	return nil
+}
+
+func (repo *PromoRepository) ReadRedemptions(code string) ([]entities.PromoRedemption, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var redemptions []entities.PromoRedemption
+	for _, redemption := range repo.redemptions {
+		if redemption.Code == code {
+			redemptions = append(redemptions, redemption)
+		}
+	}
+	return redemptions, nil
+}
+
+func (repo *PromoRepository) DeleteRedemptionByTicket(ticketID string) (entities.PromoRedemption, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, redemption := range repo.redemptions {
+		if redemption.TicketID == ticketID {
+			repo.redemptions = append(repo.redemptions[:i], repo.redemptions[i+1:]...)
+			return redemption, nil
+		}
+	}
+	return entities.PromoRedemption{}, errors.New("NOT_FOUND")
+}
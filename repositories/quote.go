@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"sync"
+	"time"
+)
+
+type QuoteRepository struct {
+	mu   sync.RWMutex
+	data []entities.PriceQuote
+}
+type QuoteRepositoryInterface interface {
+	Create(quote entities.PriceQuote) error
+	Read(id string) (entities.PriceQuote, error)
+	Delete(id string) error
+	DeleteExpired(at time.Time) int
+}
+
+func NewQuoteRepository(data []entities.PriceQuote) QuoteRepositoryInterface {
+	return &QuoteRepository{
+		data: cloneAll(data, cloneQuote),
+	}
+}
+
+func (repo *QuoteRepository) Create(quote entities.PriceQuote) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingQuote := range repo.data {
+		if existingQuote.ID == quote.ID {
+			return errors.New("quote with the same ID already exists")
+		}
+	}
+	repo.data = append(repo.data, cloneQuote(quote))
+	return nil
+}
+
+func (repo *QuoteRepository) Read(id string) (entities.PriceQuote, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, quote := range repo.data {
+		if quote.ID == id {
+			return cloneQuote(quote), nil
+		}
+	}
+	return entities.PriceQuote{}, errors.New("NOT_FOUND")
+}
+
+func (repo *QuoteRepository) Delete(id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, quote := range repo.data {
+		if quote.ID == id {
+			repo.data = append(repo.data[:i], repo.data[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
+
+func (repo *QuoteRepository) DeleteExpired(at time.Time) int {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	kept := repo.data[:0]
+	for _, quote := range repo.data {
+		if quote.Expires_at.After(at) {
+			kept = append(kept, quote)
+		}
+	}
+	removed := len(repo.data) - len(kept)
+	repo.data = kept
+	return removed
+}
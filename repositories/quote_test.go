@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"movie-app-go/entities"
+)
+
+func TestQuoteRepositoryConcurrentExpiry(t *testing.T) {
+	repo := NewQuoteRepository(nil)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			expires := now.Add(time.Hour)
+			if i%2 == 0 {
+				expires = now.Add(-time.Hour)
+			}
+			repo.Create(entities.PriceQuote{ID: fmt.Sprint(i), Seats: []string{"A1"}, Expires_at: expires})
+		}(i)
+		go func() {
+			defer wg.Done()
+			repo.DeleteExpired(now)
+		}()
+	}
+	wg.Wait()
+	repo.DeleteExpired(now)
+
+	for i := 0; i < 50; i++ {
+		_, err := repo.Read(fmt.Sprint(i))
+		if live := i%2 == 1; live != (err == nil) {
+			t.Errorf("quote %d: live %v, found %v", i, live, err == nil)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"slices"
+	"sync"
+	"time"
+)
+
+type ReviewRepository struct {
+	mu   sync.RWMutex
+	data []entities.Review
+	// version counts the writes, modified is when the last one happened
+	version  uint64
+	modified time.Time
+}
+type ReviewRepositoryInterface interface {
+	Create(review entities.Review) error
+	Read(id string) (entities.Review, error)
+	ReadAll() ([]entities.Review, error)
+	ReadByMovie(movieID int) ([]entities.Review, error)
+	Update(review entities.Review) error
+	Delete(id string) error
+	Version() (uint64, time.Time)
+}
+
+func NewReviewRepository(data []entities.Review) ReviewRepositoryInterface {
+	return &ReviewRepository{
+		data: slices.Clone(data),
+	}
+}
+
+func (repo *ReviewRepository) Create(review entities.Review) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existing := range repo.data {
+		if existing.ID == review.ID {
+			return errors.New("review with the same ID already exists")
+		}
+	}
+	repo.data = append(repo.data, review)
+	repo.changed()
+	return nil
+}
+
+func (repo *ReviewRepository) Read(id string) (entities.Review, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, review := range repo.data {
+		if review.ID == id {
+			return review, nil
+		}
+	}
+	return entities.Review{}, errors.New("NOT_FOUND")
+}
+
+func (repo *ReviewRepository) ReadAll() ([]entities.Review, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return slices.Clone(repo.data), nil
+}
+
+func (repo *ReviewRepository) ReadByMovie(movieID int) ([]entities.Review, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var reviews []entities.Review
+	for _, review := range repo.data {
+		if review.MovieID == movieID {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (repo *ReviewRepository) Update(review entities.Review) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existing := range repo.data {
+		if existing.ID == review.ID {
+			repo.data[i] = review
+			repo.changed()
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
+
+func (repo *ReviewRepository) Delete(id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existing := range repo.data {
+		if existing.ID == id {
+			repo.data = append(repo.data[:i], repo.data[i+1:]...)
+			repo.changed()
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
+
+// Version tells how many writes the reviews have seen and when the last one
+// happened, the zero time before any
+func (repo *ReviewRepository) Version() (uint64, time.Time) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return repo.version, repo.modified
+}
+
+func (repo *ReviewRepository) changed() {
+	repo.version++
+	repo.modified = time.Now()
+}
@@ -14,6 +14,7 @@ func GenerateSeats() []entities.Seat {
 				Row:    row,
 				Number: i,
 				Booked: false,
+				Type:   seatType(row, i),
 			}
 			seats = append(seats, seat)
 		}
@@ -21,3 +22,18 @@ func GenerateSeats() []entities.Seat {
 
 	return seats
 }
+
+// seatType lays out the auditorium: wheelchair spaces at the aisle ends of
+// the front row, premium seats in the middle of rows E to G and couple seats
+// along the back row
+func seatType(row string, number int) string {
+	switch {
+	case row == "A" && (number == 1 || number == 8):
+		return entities.SeatAccessible
+	case row == "H":
+		return entities.SeatCouple
+	case (row == "E" || row == "F" || row == "G") && number >= 3 && number <= 6:
+		return entities.SeatPremium
+	}
+	return entities.SeatRegular
+}
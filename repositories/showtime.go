@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"fmt"
+	"movie-app-go/entities"
+	"time"
+)
+
+// NextShowtime returns the first daily screening at clock ("HH:MM") that is
+// neither in the past nor before the release date. A zero release date is
+// ignored
+func NextShowtime(release entities.Date, clock string, now time.Time) (time.Time, error) {
+	at, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid showtime clock %q: %w", clock, err)
+	}
+
+	from := now
+	if release.After(from) {
+		from = release.In(now.Location())
+	}
+	showtime := time.Date(from.Year(), from.Month(), from.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+	if showtime.Before(from) {
+		showtime = showtime.AddDate(0, 0, 1)
+	}
+	return showtime, nil
+}
@@ -3,55 +3,92 @@ package repositories
 import (
 	"errors"
 	"movie-app-go/entities"
+	"sync"
 	"time"
 )
 
 type TicketRepository struct {
+	mu   sync.RWMutex
 	data []entities.Ticket
 }
 type TicketRepositoryInterface interface {
 	Create(ticket entities.Ticket) error
 	Read(id string) (entities.Ticket, error)
+	ReadByUser(userID string) ([]entities.Ticket, error)
+	ReadAll() ([]entities.Ticket, error)
 	Update(ticket entities.Ticket) error
 	Delete(ticket entities.Ticket) error
 }
 
 func NewTicketRepository(data []entities.Ticket) TicketRepositoryInterface {
 	return &TicketRepository{
-		data: data,
+		data: cloneAll(data, cloneTicket),
 	}
 }
 
 func (repo *TicketRepository) Create(ticket entities.Ticket) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	for _, existingTicket := range repo.data {
 		if existingTicket.ID == ticket.ID {
 			return errors.New("ticket with the same ID already exists")
 		}
 	}
-	repo.data = append(repo.data, ticket)
+	repo.data = append(repo.data, cloneTicket(ticket))
 	return nil
 }
 
 func (repo *TicketRepository) Read(id string) (entities.Ticket, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
 	for _, existingTicket := range repo.data {
 		if existingTicket.ID == id {
-			return existingTicket, nil
+			return cloneTicket(existingTicket), nil
 		}
 	}
 	return entities.Ticket{}, errors.New("NOT_FOUND")
 }
 
+func (repo *TicketRepository) ReadByUser(userID string) ([]entities.Ticket, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var tickets []entities.Ticket
+	for _, existingTicket := range repo.data {
+		if existingTicket.UserID == userID {
+			tickets = append(tickets, cloneTicket(existingTicket))
+		}
+	}
+	return tickets, nil
+}
+
+func (repo *TicketRepository) ReadAll() ([]entities.Ticket, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return cloneAll(repo.data, cloneTicket), nil
+}
+
 func (repo *TicketRepository) Update(ticket entities.Ticket) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	for i, existingTicket := range repo.data {
 		if existingTicket.ID == ticket.ID {
 			ticket.Updated_At = time.Now()
-			repo.data[i] = ticket
+			repo.data[i] = cloneTicket(ticket)
+			return nil
 		}
 	}
 	return errors.New("NOT_FOUND")
 }
 
 func (repo *TicketRepository) Delete(ticket entities.Ticket) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	for i, existingTicket := range repo.data {
 		if existingTicket.ID == ticket.ID {
 			repo.data = append(repo.data[:i], repo.data[i+1:]...)
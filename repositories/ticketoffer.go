@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"sync"
+)
+
+type TicketOfferRepository struct {
+	mu   sync.RWMutex
+	data []entities.TicketOffer
+}
+type TicketOfferRepositoryInterface interface {
+	Create(offer entities.TicketOffer) error
+	Read(id string) (entities.TicketOffer, error)
+	ReadByUser(userID string) ([]entities.TicketOffer, error)
+	ReadByTicket(ticketID string) ([]entities.TicketOffer, error)
+	Update(offer entities.TicketOffer) error
+}
+
+func NewTicketOfferRepository(data []entities.TicketOffer) TicketOfferRepositoryInterface {
+	return &TicketOfferRepository{
+		data: cloneAll(data, cloneOffer),
+	}
+}
+
+func (repo *TicketOfferRepository) Create(offer entities.TicketOffer) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingOffer := range repo.data {
+		if existingOffer.ID == offer.ID {
+			return errors.New("ticket offer with the same id already exists")
+		}
+	}
+	repo.data = append(repo.data, cloneOffer(offer))
+	return nil
+}
+
+func (repo *TicketOfferRepository) Read(id string) (entities.TicketOffer, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, offer := range repo.data {
+		if offer.ID == id {
+			return cloneOffer(offer), nil
+		}
+	}
+	return entities.TicketOffer{}, errors.New("NOT_FOUND")
+}
+
+// ReadByUser returns the offers the user sent or received
+func (repo *TicketOfferRepository) ReadByUser(userID string) ([]entities.TicketOffer, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	offers := make([]entities.TicketOffer, 0)
+	for _, offer := range repo.data {
+		if offer.From_user_id == userID || offer.To_user_id == userID {
+			offers = append(offers, cloneOffer(offer))
+		}
+	}
+	return offers, nil
+}
+
+func (repo *TicketOfferRepository) ReadByTicket(ticketID string) ([]entities.TicketOffer, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	offers := make([]entities.TicketOffer, 0)
+	for _, offer := range repo.data {
+		if offer.TicketID == ticketID {
+			offers = append(offers, cloneOffer(offer))
+		}
+	}
+	return offers, nil
+}
+
+func (repo *TicketOfferRepository) Update(offer entities.TicketOffer) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingOffer := range repo.data {
+		if existingOffer.ID == offer.ID {
+			repo.data[i] = cloneOffer(offer)
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
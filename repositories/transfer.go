@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"slices"
+	"sync"
+)
+
+type TransferRepository struct {
+	mu   sync.RWMutex
+	data []entities.Transfer
+}
+type TransferRepositoryInterface interface {
+	Create(transfer entities.Transfer) error
+	Read(id string) (entities.Transfer, error)
+	ReadByUser(userID string) ([]entities.Transfer, error)
+	Update(transfer entities.Transfer) error
+}
+
+func NewTransferRepository(data []entities.Transfer) TransferRepositoryInterface {
+	return &TransferRepository{
+		data: slices.Clone(data),
+	}
+}
+
+func (repo *TransferRepository) Create(transfer entities.Transfer) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingTransfer := range repo.data {
+		if existingTransfer.ID == transfer.ID {
+			return errors.New("transfer with the same id already exists")
+		}
+	}
+	repo.data = append(repo.data, transfer)
+	return nil
+}
+
+func (repo *TransferRepository) Read(id string) (entities.Transfer, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, transfer := range repo.data {
+		if transfer.ID == id {
+			return transfer, nil
+		}
+	}
+	return entities.Transfer{}, errors.New("NOT_FOUND")
+}
+
+// ReadByUser returns every transfer the user sent or received, oldest first
+func (repo *TransferRepository) ReadByUser(userID string) ([]entities.Transfer, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	transfers := make([]entities.Transfer, 0)
+	for _, transfer := range repo.data {
+		if transfer.From_user_id == userID || transfer.To_user_id == userID {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+func (repo *TransferRepository) Update(transfer entities.Transfer) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingTransfer := range repo.data {
+		if existingTransfer.ID == transfer.ID {
+			repo.data[i] = transfer
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
@@ -3,15 +3,18 @@ package repositories
 import (
 	"errors"
 	"movie-app-go/entities"
+	"sync"
 )
 
 type UserRepository struct {
+	mu   sync.RWMutex
 	data []entities.User
 }
 type UserRepositoryInterface interface {
 	Create(user entities.User) error
 	GetByUsername(username string) (entities.User, error)
-	// Read(id string) (entities.User, error)
+	Read(id string) (entities.User, error)
+	ReadByGuardian(guardianID string) ([]entities.User, error)
 	// ReadAll() ([]entities.User, error)
 	Update(user entities.User) error
 	// Delete(user *entities.User) error
@@ -24,31 +27,76 @@ type UserRepositoryInterface interface {
 
 func NewUserRepository(data []entities.User) UserRepositoryInterface {
 	return &UserRepository{
-		data: data,
+		data: cloneAll(data, cloneUser),
 	}
 }
+
+// Create rejects a taken username. Dependent profiles that cannot log in
+// have no username and never clash
 func (repo *UserRepository) Create(user entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	for _, existingUser := range repo.data {
+		if existingUser.Username == "" {
+			continue
+		}
 		if existingUser.Username == user.Username {
 			return errors.New("user with the same Username already exists")
 		}
 	}
-	repo.data = append(repo.data, user)
+	repo.data = append(repo.data, cloneUser(user))
 	return nil
 }
+
+// GetByUsername never finds the username-less dependent profiles
 func (repo *UserRepository) GetByUsername(username string) (entities.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if username == "" {
+		return entities.User{}, errors.New("EMPTY_DATA")
+	}
 	for _, user := range repo.data {
 		if user.Username == username {
-			return user, nil
+			return cloneUser(user), nil
 		}
 	}
 	return entities.User{}, errors.New("EMPTY_DATA")
 }
 
+func (repo *UserRepository) Read(id string) (entities.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, user := range repo.data {
+		if user.ID == id {
+			return cloneUser(user), nil
+		}
+	}
+	return entities.User{}, errors.New("EMPTY_DATA")
+}
+
+func (repo *UserRepository) ReadByGuardian(guardianID string) ([]entities.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var users []entities.User
+	for _, user := range repo.data {
+		if user.Guardian_id == guardianID {
+			users = append(users, cloneUser(user))
+		}
+	}
+	return users, nil
+}
+
 func (repo *UserRepository) Update(user entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
 	for i, existingUser := range repo.data {
-		if existingUser.Username == user.Username {
-			repo.data[i] = user
+		if existingUser.ID == user.ID {
+			repo.data[i] = cloneUser(user)
 			return nil
 		}
 	}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"sync"
+)
+
+type WaitlistRepository struct {
+	mu   sync.RWMutex
+	data []entities.WaitlistEntry
+}
+type WaitlistRepositoryInterface interface {
+	Create(entry entities.WaitlistEntry) error
+	Read(id string) (entities.WaitlistEntry, error)
+	ReadAll() ([]entities.WaitlistEntry, error)
+	ReadByUser(userID string) ([]entities.WaitlistEntry, error)
+	Update(entry entities.WaitlistEntry) error
+}
+
+func NewWaitlistRepository(data []entities.WaitlistEntry) WaitlistRepositoryInterface {
+	return &WaitlistRepository{
+		data: cloneAll(data, cloneWaitlistEntry),
+	}
+}
+
+func (repo *WaitlistRepository) Create(entry entities.WaitlistEntry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingEntry := range repo.data {
+		if existingEntry.ID == entry.ID {
+			return errors.New("waitlist entry with the same id already exists")
+		}
+	}
+	repo.data = append(repo.data, cloneWaitlistEntry(entry))
+	return nil
+}
+
+func (repo *WaitlistRepository) Read(id string) (entities.WaitlistEntry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, entry := range repo.data {
+		if entry.ID == id {
+			return cloneWaitlistEntry(entry), nil
+		}
+	}
+	return entities.WaitlistEntry{}, errors.New("NOT_FOUND")
+}
+
+// ReadAll returns every entry in the order they joined
+func (repo *WaitlistRepository) ReadAll() ([]entities.WaitlistEntry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return cloneAll(repo.data, cloneWaitlistEntry), nil
+}
+
+func (repo *WaitlistRepository) ReadByUser(userID string) ([]entities.WaitlistEntry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	entries := make([]entities.WaitlistEntry, 0)
+	for _, entry := range repo.data {
+		if entry.UserID == userID {
+			entries = append(entries, cloneWaitlistEntry(entry))
+		}
+	}
+	return entries, nil
+}
+
+func (repo *WaitlistRepository) Update(entry entities.WaitlistEntry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingEntry := range repo.data {
+		if existingEntry.ID == entry.ID {
+			repo.data[i] = cloneWaitlistEntry(entry)
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
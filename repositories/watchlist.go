@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"errors"
+	"movie-app-go/entities"
+	"slices"
+	"sync"
+)
+
+type WatchlistRepository struct {
+	mu   sync.RWMutex
+	data []entities.WatchlistItem
+}
+type WatchlistRepositoryInterface interface {
+	Create(item entities.WatchlistItem) error
+	ReadAll() ([]entities.WatchlistItem, error)
+	ReadByUser(userID string) ([]entities.WatchlistItem, error)
+	Update(item entities.WatchlistItem) error
+	Delete(id string) error
+}
+
+func NewWatchlistRepository(data []entities.WatchlistItem) WatchlistRepositoryInterface {
+	return &WatchlistRepository{
+		data: slices.Clone(data),
+	}
+}
+
+func (repo *WatchlistRepository) Create(item entities.WatchlistItem) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existingItem := range repo.data {
+		if existingItem.ID == item.ID {
+			return errors.New("watchlist item with the same id already exists")
+		}
+	}
+	repo.data = append(repo.data, item)
+	return nil
+}
+
+func (repo *WatchlistRepository) ReadAll() ([]entities.WatchlistItem, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return slices.Clone(repo.data), nil
+}
+
+func (repo *WatchlistRepository) ReadByUser(userID string) ([]entities.WatchlistItem, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var items []entities.WatchlistItem
+	for _, item := range repo.data {
+		if item.UserID == userID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (repo *WatchlistRepository) Update(item entities.WatchlistItem) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingItem := range repo.data {
+		if existingItem.ID == item.ID {
+			repo.data[i] = item
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}
+
+func (repo *WatchlistRepository) Delete(id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existingItem := range repo.data {
+		if existingItem.ID == id {
+			repo.data = append(repo.data[:i], repo.data[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("NOT_FOUND")
+}